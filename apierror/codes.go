@@ -0,0 +1,34 @@
+package apierror
+
+// Stable codes of the errors sent in ErrorResponse.Code, clients tell
+// the errors apart by them.
+const (
+	CodeValidationFailed         = "VALIDATION_FAILED"
+	CodeInternalError            = "INTERNAL_ERROR"
+	CodeTimeout                  = "TIMEOUT"
+	CodeEmptyDto                 = "EMPTY_DTO"
+	CodeQuestionNotFound         = "QUESTION_NOT_FOUND"
+	CodeAnswerNotFound           = "ANSWER_NOT_FOUND"
+	CodeQuestionNotFoundInTrash  = "QUESTION_NOT_FOUND_IN_TRASH"
+	CodeAnswerNotFoundInTrash    = "ANSWER_NOT_FOUND_IN_TRASH"
+	CodeCommentNotFound          = "COMMENT_NOT_FOUND"
+	CodeRevisionNotFound         = "REVISION_NOT_FOUND"
+	CodeUserNotFound             = "USER_NOT_FOUND"
+	CodeUserAlreadyExists        = "USER_ALREADY_EXISTS"
+	CodeInvalidCredentials       = "INVALID_CREDENTIALS"
+	CodeUnauthorized             = "UNAUTHORIZED"
+	CodeInvalidToken             = "INVALID_TOKEN"
+	CodeForbidden                = "FORBIDDEN"
+	CodeInvalidCursor            = "INVALID_CURSOR"
+	CodeEmptySearchQuery         = "EMPTY_SEARCH_QUERY"
+	CodeInvalidLogLevel          = "INVALID_LOG_LEVEL"
+	CodeInvalidId                = "INVALID_ID"
+	CodeInvalidAnswerId          = "INVALID_ANSWER_ID"
+	CodeInvalidRevisionId        = "INVALID_REVISION_ID"
+	CodeEmptyBody                = "EMPTY_BODY"
+	CodeInvalidBody              = "INVALID_BODY"
+	CodeBodyTooLarge             = "BODY_TOO_LARGE"
+	CodeInvalidIdempotencyKey    = "INVALID_IDEMPOTENCY_KEY"
+	CodeIdempotencyKeyReused     = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyKeyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+)
@@ -5,21 +5,42 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/gengeo7/highlitent/i18n"
 	"github.com/gengeo7/highlitent/logger"
 	"github.com/gengeo7/highlitent/types/common"
 )
 
+// ProblemType is sent as type of every problem, the kind of the error
+// is told by Code instead of a documentation URI.
+const ProblemType = "about:blank"
+
+const ProblemContentType = "application/problem+json"
+
+// ErrorResponse is an RFC 7807 problem details object. Code is a stable
+// machine-readable code of the error, Detail is a message for the user
+// in the language picked from Accept-Language.
 type ErrorResponse struct {
-	Error  string            `json:"error"`
-	Fields map[string]string `json:"fields,omitempty"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      string            `json:"code"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	RequestID string            `json:"requestID,omitempty"`
 }
 
+// ApiError is sent to the client with the message of Code in the
+// language of the request, Msg holds it in the default language for
+// logs.
 type ApiError struct {
 	StatusCode    int
+	Code          string
 	OriginalError error
 	Msg           string
 }
 
+// ValidationError holds the already translated errors of the fields.
 type ValidationError struct {
 	Fields map[string]string
 	Msg    string
@@ -33,57 +54,60 @@ func (a ApiError) Error() string {
 	return a.Msg
 }
 
-func NewApiError(code int, msg string, err error) *ApiError {
+func NewApiError(status int, code string, err error) *ApiError {
 	return &ApiError{
-		StatusCode:    code,
-		Msg:           msg,
+		StatusCode:    status,
+		Code:          code,
+		Msg:           messages.Message(i18n.Default(), code),
 		OriginalError: err,
 	}
 }
 
-func NewValidationError(msg string, fields map[string]string) *ValidationError {
+func NewValidationError(fields map[string]string) *ValidationError {
 	return &ValidationError{
 		Fields: fields,
-		Msg:    msg,
+		Msg:    messages.Message(i18n.Default(), CodeValidationFailed),
 	}
 }
 
 func SendError(w http.ResponseWriter, r *http.Request, err error) {
 	var response ErrorResponse
-	var statusCode int
 	var ae *ApiError
 	var ve *ValidationError
 	if errors.As(err, &ae) {
-		statusCode = ae.StatusCode
-		response.Error = ae.Msg
+		response.Status = ae.StatusCode
+		response.Code = ae.Code
 		if r != nil && ae.OriginalError != nil {
-			logger.Error("internal error",
-				"route", r.RequestURI,
-				"error", ae.OriginalError.Error(),
-				"id", r.Context().Value(common.RequestIdKey{}),
-			)
+			logger.ErrorCtx(r.Context(), "internal error", "error", ae.OriginalError.Error())
 		}
 
 	} else if errors.As(err, &ve) {
-		statusCode = http.StatusBadRequest
+		response.Status = http.StatusBadRequest
+		response.Code = CodeValidationFailed
 		response.Fields = ve.Fields
-		response.Error = ve.Msg
 
 	} else {
-		statusCode = http.StatusInternalServerError
-		response.Error = "unhandled internal error"
+		response.Status = http.StatusInternalServerError
+		response.Code = CodeInternalError
 		if r != nil {
-			logger.Error("unhandled internal error",
-				"route", r.RequestURI,
-				"error", err.Error(),
-				"id", r.Context().Value(common.RequestIdKey{}),
-			)
+			logger.ErrorCtx(r.Context(), "unhandled internal error", "error", err.Error())
 		} else {
 			logger.Error("unhandled internal error", "error", err.Error())
 		}
 	}
 
-	w.Header().Set("Content-type", "application/json")
-	w.WriteHeader(statusCode)
+	lang := i18n.Default()
+	if r != nil {
+		lang = i18n.FromContext(r.Context())
+		response.Instance = r.URL.Path
+		response.RequestID, _ = r.Context().Value(common.RequestIdKey{}).(string)
+	}
+	response.Type = ProblemType
+	response.Title = http.StatusText(response.Status)
+	response.Detail = messages.Message(lang, response.Code)
+
+	w.Header().Set("Content-type", ProblemContentType)
+	w.Header().Set("Content-Language", string(lang))
+	w.WriteHeader(response.Status)
 	json.NewEncoder(w).Encode(response)
 }
@@ -0,0 +1,114 @@
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gengeo7/highlitent/i18n"
+	"github.com/gengeo7/highlitent/types/common"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSendError(t *testing.T) {
+	tests := []struct {
+		name string
+		lang i18n.Lang
+		err  error
+		want ErrorResponse
+	}{
+		{
+			name: "api error",
+			lang: i18n.Russian,
+			err:  NewApiError(http.StatusNotFound, CodeQuestionNotFound, nil),
+			want: ErrorResponse{
+				Type:      ProblemType,
+				Title:     "Not Found",
+				Status:    http.StatusNotFound,
+				Detail:    "вопрос не найден",
+				Instance:  "/questions/1",
+				Code:      "QUESTION_NOT_FOUND",
+				RequestID: "req-1",
+			},
+		},
+		{
+			name: "api error in english",
+			lang: i18n.English,
+			err:  NewApiError(http.StatusNotFound, CodeQuestionNotFound, nil),
+			want: ErrorResponse{
+				Type:      ProblemType,
+				Title:     "Not Found",
+				Status:    http.StatusNotFound,
+				Detail:    "question not found",
+				Instance:  "/questions/1",
+				Code:      "QUESTION_NOT_FOUND",
+				RequestID: "req-1",
+			},
+		},
+		{
+			name: "validation error",
+			lang: i18n.Russian,
+			err:  NewValidationError(map[string]string{"text": "text обязательное поле"}),
+			want: ErrorResponse{
+				Type:      ProblemType,
+				Title:     "Bad Request",
+				Status:    http.StatusBadRequest,
+				Detail:    "ошибка валидации",
+				Instance:  "/questions/1",
+				Code:      CodeValidationFailed,
+				Fields:    map[string]string{"text": "text обязательное поле"},
+				RequestID: "req-1",
+			},
+		},
+		{
+			name: "unhandled error",
+			lang: i18n.Russian,
+			err:  errors.New("boom"),
+			want: ErrorResponse{
+				Type:      ProblemType,
+				Title:     "Internal Server Error",
+				Status:    http.StatusInternalServerError,
+				Detail:    "непредвиденная ошибка",
+				Instance:  "/questions/1",
+				Code:      CodeInternalError,
+				RequestID: "req-1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/questions/1?sort=asc", nil)
+			ctx := context.WithValue(r.Context(), common.RequestIdKey{}, "req-1")
+			r = r.WithContext(i18n.WithLang(ctx, tt.lang))
+			w := httptest.NewRecorder()
+
+			SendError(w, r, tt.err)
+
+			if w.Code != tt.want.Status {
+				t.Errorf("status = %d, want: %d", w.Code, tt.want.Status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+				t.Errorf("Content-Type = %q, want: %q", ct, ProblemContentType)
+			}
+			if cl := w.Header().Get("Content-Language"); cl != string(tt.lang) {
+				t.Errorf("Content-Language = %q, want: %q", cl, tt.lang)
+			}
+			var got ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SendError() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMessagesAreComplete(t *testing.T) {
+	for _, key := range messages.Missing() {
+		t.Errorf("%s has no message", key)
+	}
+}
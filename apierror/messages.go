@@ -0,0 +1,119 @@
+package apierror
+
+import "github.com/gengeo7/highlitent/i18n"
+
+// messages maps an error code to its message in every language.
+var messages = i18n.Catalog{
+	CodeValidationFailed: {
+		i18n.Russian: "ошибка валидации",
+		i18n.English: "validation failed",
+	},
+	CodeInternalError: {
+		i18n.Russian: "непредвиденная ошибка",
+		i18n.English: "unexpected error",
+	},
+	CodeTimeout: {
+		i18n.Russian: "достигнут лимит по времени",
+		i18n.English: "time limit exceeded",
+	},
+	CodeEmptyDto: {
+		i18n.Russian: "отсутствует dto",
+		i18n.English: "dto is missing",
+	},
+	CodeQuestionNotFound: {
+		i18n.Russian: "вопрос не найден",
+		i18n.English: "question not found",
+	},
+	CodeAnswerNotFound: {
+		i18n.Russian: "ответ не найден",
+		i18n.English: "answer not found",
+	},
+	CodeQuestionNotFoundInTrash: {
+		i18n.Russian: "вопрос не найден в корзине",
+		i18n.English: "question not found in trash",
+	},
+	CodeAnswerNotFoundInTrash: {
+		i18n.Russian: "ответ не найден в корзине",
+		i18n.English: "answer not found in trash",
+	},
+	CodeCommentNotFound: {
+		i18n.Russian: "комментарий не найден",
+		i18n.English: "comment not found",
+	},
+	CodeRevisionNotFound: {
+		i18n.Russian: "ревизия не найдена",
+		i18n.English: "revision not found",
+	},
+	CodeUserNotFound: {
+		i18n.Russian: "пользователь не найден",
+		i18n.English: "user not found",
+	},
+	CodeUserAlreadyExists: {
+		i18n.Russian: "пользователь с таким email уже существует",
+		i18n.English: "user with this email already exists",
+	},
+	CodeInvalidCredentials: {
+		i18n.Russian: "неверный email или пароль",
+		i18n.English: "wrong email or password",
+	},
+	CodeUnauthorized: {
+		i18n.Russian: "требуется авторизация",
+		i18n.English: "authorization required",
+	},
+	CodeInvalidToken: {
+		i18n.Russian: "недействительный токен",
+		i18n.English: "invalid token",
+	},
+	CodeForbidden: {
+		i18n.Russian: "недостаточно прав",
+		i18n.English: "not enough permissions",
+	},
+	CodeInvalidCursor: {
+		i18n.Russian: "некорректный курсор",
+		i18n.English: "invalid cursor",
+	},
+	CodeEmptySearchQuery: {
+		i18n.Russian: "пустой поисковый запрос",
+		i18n.English: "empty search query",
+	},
+	CodeInvalidLogLevel: {
+		i18n.Russian: "неизвестный уровень логов",
+		i18n.English: "unknown log level",
+	},
+	CodeInvalidId: {
+		i18n.Russian: "некорректный id",
+		i18n.English: "invalid id",
+	},
+	CodeInvalidAnswerId: {
+		i18n.Russian: "некорректный id ответа",
+		i18n.English: "invalid answer id",
+	},
+	CodeInvalidRevisionId: {
+		i18n.Russian: "некорректный id ревизии",
+		i18n.English: "invalid revision id",
+	},
+	CodeEmptyBody: {
+		i18n.Russian: "пустое тело запроса",
+		i18n.English: "request body is empty",
+	},
+	CodeInvalidBody: {
+		i18n.Russian: "ошибка чтения тела запроса",
+		i18n.English: "request body can't be read",
+	},
+	CodeBodyTooLarge: {
+		i18n.Russian: "слишком большое тело запроса",
+		i18n.English: "request body is too large",
+	},
+	CodeInvalidIdempotencyKey: {
+		i18n.Russian: "некорректный Idempotency-Key",
+		i18n.English: "invalid Idempotency-Key",
+	},
+	CodeIdempotencyKeyReused: {
+		i18n.Russian: "Idempotency-Key уже использован для другого запроса",
+		i18n.English: "Idempotency-Key was already used for another request",
+	},
+	CodeIdempotencyKeyInProgress: {
+		i18n.Russian: "запрос с этим Idempotency-Key еще выполняется",
+		i18n.English: "request with this Idempotency-Key is still in progress",
+	},
+}
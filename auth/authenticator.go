@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+)
+
+type UserGetter interface {
+	UserGet(ctx context.Context, id uuid.UUID) (*users.User, error)
+}
+
+// Authenticator checks the token and loads its user from the storage
+// on every request, so a changed role applies to tokens issued before
+// the change.
+type Authenticator struct {
+	tokens *TokenManager
+	users  UserGetter
+}
+
+func NewAuthenticator(tokens *TokenManager, users UserGetter) *Authenticator {
+	return &Authenticator{tokens: tokens, users: users}
+}
+
+// Authenticate returns the user of the token. Tokens of users that
+// don't exist anymore are ErrInvalidToken, storage errors are returned
+// as is.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*users.User, error) {
+	userID, err := a.tokens.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	user, err := a.users.UserGet(ctx, userID)
+	if err != nil {
+		if storage.IsErrNotFound(err) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	return user, nil
+}
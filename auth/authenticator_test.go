@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+)
+
+type mockUserGetter struct {
+	ReturnedValue *users.User
+	ReturnedError error
+}
+
+func (m *mockUserGetter) UserGet(ctx context.Context, id uuid.UUID) (*users.User, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestAuthenticate(t *testing.T) {
+	tokens := NewTokenManager("secret", time.Hour)
+	userID := uuid.New()
+	token, _, err := tokens.Issue(userID)
+	if err != nil {
+		t.Fatalf("Issue() failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		userGetter *mockUserGetter
+		wantRole   users.Role
+		wantErr    error
+	}{
+		{
+			name:       "current role",
+			token:      token,
+			userGetter: &mockUserGetter{ReturnedValue: &users.User{ID: userID, Role: users.RoleUser}},
+			wantRole:   users.RoleUser,
+			wantErr:    nil,
+		},
+		{
+			name:       "invalid token",
+			token:      "not.a.token",
+			userGetter: &mockUserGetter{ReturnedValue: &users.User{ID: userID, Role: users.RoleAdmin}},
+			wantErr:    ErrInvalidToken,
+		},
+		{
+			name:       "user removed",
+			token:      token,
+			userGetter: &mockUserGetter{ReturnedError: storage.ErrDbNotFound},
+			wantErr:    ErrInvalidToken,
+		},
+		{
+			name:       "storage error",
+			token:      token,
+			userGetter: &mockUserGetter{ReturnedError: context.DeadlineExceeded},
+			wantErr:    context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := NewAuthenticator(tokens, tt.userGetter).Authenticate(context.Background(), tt.token)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("Authenticate(): %v, want: %v", gotErr, tt.wantErr)
+			}
+			if gotErr != nil {
+				return
+			}
+			if got.ID != userID || got.Role != tt.wantRole {
+				t.Errorf("Authenticate() = %+v, want role %v", got, tt.wantRole)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParse(t *testing.T) {
+	userID := uuid.New()
+	valid, _, err := NewTokenManager("secret", time.Hour).Issue(userID)
+	if err != nil {
+		t.Fatalf("Issue() failed: %v", err)
+	}
+	expired, _, err := NewTokenManager("secret", -time.Hour).Issue(userID)
+	if err != nil {
+		t.Fatalf("Issue() failed: %v", err)
+	}
+	otherSecret, _, err := NewTokenManager("other", time.Hour).Issue(userID)
+	if err != nil {
+		t.Fatalf("Issue() failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    uuid.UUID
+		wantErr error
+	}{
+		{
+			name:    "ok",
+			token:   valid,
+			want:    userID,
+			wantErr: nil,
+		},
+		{
+			name:    "expired",
+			token:   expired,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "other secret",
+			token:   otherSecret,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "garbage",
+			token:   "not.a.token",
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := NewTokenManager("secret", time.Hour).Parse(tt.token)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("Parse(): %v, want: %v", gotErr, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package authz
+
+import (
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+)
+
+type Action string
+
+const (
+	ActionEdit    Action = "edit"
+	ActionDelete  Action = "delete"
+	ActionAccept  Action = "accept"
+	ActionRestore Action = "restore"
+	ActionPurge   Action = "purge"
+	// ActionManageLogs is not tied to content, authorID is nil for it
+	ActionManageLogs Action = "manage_logs"
+	// ActionViewEmail is checked against the user whose email is
+	// shown, authorID is the id of that user
+	ActionViewEmail Action = "view_email"
+	// ActionManageRoles is checked against the user whose role is
+	// changed, authorID is the id of that user
+	ActionManageRoles Action = "manage_roles"
+	// ActionViewTrash lets content in the trash be read, e.g. its
+	// revisions, authorID is nil for it
+	ActionViewTrash Action = "view_trash"
+)
+
+// Actor is the authenticated user performing a request.
+type Actor struct {
+	UserID uuid.UUID
+	Role   users.Role
+}
+
+// Policy decides whether actor may perform action on content
+// written by authorID. authorID is nil for content without an
+// author, e.g. questions created before users existed.
+type Policy interface {
+	Can(actor Actor, action Action, authorID *uuid.UUID) bool
+}
+
+// OwnerPolicy lets authors manage their own content and lets
+// moderators and admins manage any content. Accepting an answer is
+// left to the author of the question only, deleting content for good,
+// managing logs and roles is left to admins only. Emails are shown to the
+// user and admins, the trash to moderators and admins.
+type OwnerPolicy struct{}
+
+func NewOwnerPolicy() *OwnerPolicy {
+	return &OwnerPolicy{}
+}
+
+func (p *OwnerPolicy) Can(actor Actor, action Action, authorID *uuid.UUID) bool {
+	switch action {
+	case ActionPurge, ActionManageLogs, ActionManageRoles:
+		return actor.Role == users.RoleAdmin
+	case ActionViewEmail:
+		if actor.Role == users.RoleAdmin {
+			return true
+		}
+	case ActionViewTrash:
+		return actor.Role == users.RoleAdmin || actor.Role == users.RoleModerator
+	case ActionAccept:
+	default:
+		if actor.Role == users.RoleAdmin || actor.Role == users.RoleModerator {
+			return true
+		}
+	}
+	return authorID != nil && *authorID == actor.UserID
+}
@@ -0,0 +1,191 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+)
+
+func TestOwnerPolicyCan(t *testing.T) {
+	author := uuid.New()
+	other := uuid.New()
+
+	tests := []struct {
+		name     string
+		actor    Actor
+		action   Action
+		authorID *uuid.UUID
+		want     bool
+	}{
+		{
+			name:     "author edits",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionEdit,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "author deletes",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionDelete,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "other user",
+			actor:    Actor{UserID: other, Role: users.RoleUser},
+			action:   ActionDelete,
+			authorID: &author,
+			want:     false,
+		},
+		{
+			name:     "user on content without author",
+			actor:    Actor{UserID: other, Role: users.RoleUser},
+			action:   ActionEdit,
+			authorID: nil,
+			want:     false,
+		},
+		{
+			name:     "moderator",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionDelete,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "author accepts",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionAccept,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "moderator accepts",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionAccept,
+			authorID: &author,
+			want:     false,
+		},
+		{
+			name:     "author restores",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionRestore,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "moderator restores",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionRestore,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "author purges",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionPurge,
+			authorID: &author,
+			want:     false,
+		},
+		{
+			name:     "moderator purges",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionPurge,
+			authorID: &author,
+			want:     false,
+		},
+		{
+			name:     "admin purges",
+			actor:    Actor{UserID: other, Role: users.RoleAdmin},
+			action:   ActionPurge,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "moderator manages logs",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionManageLogs,
+			authorID: nil,
+			want:     false,
+		},
+		{
+			name:     "admin manages logs",
+			actor:    Actor{UserID: other, Role: users.RoleAdmin},
+			action:   ActionManageLogs,
+			authorID: nil,
+			want:     true,
+		},
+		{
+			name:     "user manages own role",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionManageRoles,
+			authorID: &author,
+			want:     false,
+		},
+		{
+			name:     "moderator manages roles",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionManageRoles,
+			authorID: &author,
+			want:     false,
+		},
+		{
+			name:     "admin manages roles",
+			actor:    Actor{UserID: other, Role: users.RoleAdmin},
+			action:   ActionManageRoles,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "user views trash",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionViewTrash,
+			authorID: nil,
+			want:     false,
+		},
+		{
+			name:     "moderator views trash",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionViewTrash,
+			authorID: nil,
+			want:     true,
+		},
+		{
+			name:     "user views own email",
+			actor:    Actor{UserID: author, Role: users.RoleUser},
+			action:   ActionViewEmail,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "moderator views email",
+			actor:    Actor{UserID: other, Role: users.RoleModerator},
+			action:   ActionViewEmail,
+			authorID: &author,
+			want:     false,
+		},
+		{
+			name:     "admin views email",
+			actor:    Actor{UserID: other, Role: users.RoleAdmin},
+			action:   ActionViewEmail,
+			authorID: &author,
+			want:     true,
+		},
+		{
+			name:     "admin on content without author",
+			actor:    Actor{UserID: other, Role: users.RoleAdmin},
+			action:   ActionEdit,
+			authorID: nil,
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewOwnerPolicy().Can(tt.actor, tt.action, tt.authorID)
+			if got != tt.want {
+				t.Errorf("Can() = %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
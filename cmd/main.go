@@ -1,19 +1,104 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os/signal"
 	"runtime/debug"
+	"syscall"
 	"time"
 
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/authz"
 	"github.com/gengeo7/highlitent/config"
 	"github.com/gengeo7/highlitent/controllers/answers"
+	authController "github.com/gengeo7/highlitent/controllers/auth"
+	"github.com/gengeo7/highlitent/controllers/comments"
+	"github.com/gengeo7/highlitent/controllers/health"
+	"github.com/gengeo7/highlitent/controllers/logs"
 	"github.com/gengeo7/highlitent/controllers/questions"
+	"github.com/gengeo7/highlitent/controllers/search"
+	"github.com/gengeo7/highlitent/controllers/tags"
+	"github.com/gengeo7/highlitent/controllers/users"
 	"github.com/gengeo7/highlitent/logger"
+	"github.com/gengeo7/highlitent/metrics"
 	"github.com/gengeo7/highlitent/middleware"
+	usersService "github.com/gengeo7/highlitent/services/users"
+	answersStorage "github.com/gengeo7/highlitent/storage/answers"
+	commentsStorage "github.com/gengeo7/highlitent/storage/comments"
 	"github.com/gengeo7/highlitent/storage/gormdb"
+	healthStorage "github.com/gengeo7/highlitent/storage/health"
+	idempotencyStorage "github.com/gengeo7/highlitent/storage/idempotency"
+	"github.com/gengeo7/highlitent/storage/memory"
+	questionsStorage "github.com/gengeo7/highlitent/storage/questions"
+	searchStorage "github.com/gengeo7/highlitent/storage/search"
+	"github.com/gengeo7/highlitent/storage/sqlitedb"
+	tagsStorage "github.com/gengeo7/highlitent/storage/tags"
+	usersStorage "github.com/gengeo7/highlitent/storage/users"
+	"github.com/gengeo7/highlitent/tracing"
 )
 
+type Storage interface {
+	io.Closer
+	questionsStorage.Storage
+	answersStorage.Storage
+	searchStorage.Storage
+	usersStorage.Storage
+	tagsStorage.Storage
+	commentsStorage.Storage
+	healthStorage.Storage
+	idempotencyStorage.Storage
+}
+
+func openStorage() (Storage, error) {
+	switch config.Conf.StorageDriver {
+	case config.Sqlite:
+		db := sqlitedb.NewDb()
+		err := db.Open(config.Conf.SqlitePath)
+		if err != nil {
+			return nil, err
+		}
+		err = db.Migrate(config.Conf.MigrationPath)
+		if err != nil {
+			return nil, err
+		}
+		err = db.Instrument("sqlite")
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+
+	case config.Memory:
+		return memory.NewDb(), nil
+
+	default:
+		db := gormdb.NewDb()
+		dsnConfig := gormdb.DsnConfig{
+			Host:     config.Conf.PostgresHost,
+			Port:     config.Conf.PostgresPort,
+			User:     config.Conf.PostgresUser,
+			Password: config.Conf.PostgresPassword,
+			Database: config.Conf.PostgresDatabase,
+		}
+		err := db.Open(&dsnConfig)
+		if err != nil {
+			return nil, err
+		}
+		err = db.Migrate(config.Conf.MigrationPath)
+		if err != nil {
+			return nil, err
+		}
+		err = db.Instrument("postgres")
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+}
+
 func main() {
 	err := config.Initialize()
 	if err != nil {
@@ -21,41 +106,68 @@ func main() {
 		return
 	}
 
-	logger.Init()
+	err = logger.Init()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer logger.Close()
 	defer func() {
 		if err := recover(); err != nil {
 			logger.Error("PANIC", "error", err, "stacktrace", string(debug.Stack()))
 		}
 	}()
 
-	db := gormdb.NewDb()
-	dsnConfig := gormdb.DsnConfig{
-		Host:     config.Conf.PostgresHost,
-		Port:     config.Conf.PostgresPort,
-		User:     config.Conf.PostgresUser,
-		Password: config.Conf.PostgresPassword,
-		Database: config.Conf.PostgresDatabase,
+	err = tracing.Init()
+	if err != nil {
+		logger.Error(err.Error())
+		return
 	}
-	err = db.Open(&dsnConfig)
+
+	db, err := openStorage()
 	if err != nil {
 		logger.Error(err.Error())
 		return
 	}
-	err = db.Migrate(config.Conf.MigrationPath)
+
+	err = usersService.PromoteAdmin(context.Background(), db, config.Conf.AdminEmail)
 	if err != nil {
 		logger.Error(err.Error())
 		return
 	}
 
+	tokens := auth.NewTokenManager(config.Conf.JwtSecret, config.Conf.JwtTTL)
+	authenticator := auth.NewAuthenticator(tokens, db)
+	policy := authz.NewOwnerPolicy()
+
 	mux := http.NewServeMux()
-	answersController := answers.NewAnswersController(db)
+	answersController := answers.NewAnswersController(db, db, authenticator, policy, config.Conf.IdempotencyTTL)
 	answersController.RegisterController(mux)
-	questionsController := questions.NewQuestionsController(db)
+	commentsController := comments.NewCommentsController(db, authenticator)
+	commentsController.RegisterController(mux)
+	healthController := health.NewHealthController(db)
+	healthController.RegisterController(mux)
+	questionsController := questions.NewQuestionsController(db, db, authenticator, policy, config.Conf.IdempotencyTTL)
 	questionsController.RegisterController(mux)
+	searchController := search.NewSearchController(db)
+	searchController.RegisterController(mux)
+	tagsController := tags.NewTagsController(db)
+	tagsController.RegisterController(mux)
+	usersController := users.NewUsersController(db, authenticator, policy, config.Conf.AdminEmail)
+	usersController.RegisterController(mux)
+	loginController := authController.NewAuthController(db, tokens)
+	loginController.RegisterController(mux)
+	logsController := logs.NewLogsController(authenticator, policy)
+	logsController.RegisterController(mux)
+	mux.Handle("GET /metrics", metrics.Handler())
 
-	handler := middleware.Log(
-		middleware.TimeElapsed(
-			middleware.Recoverer(mux),
+	handler := middleware.Trace(
+		middleware.Log(
+			middleware.Language(
+				middleware.TimeElapsed(
+					middleware.Recoverer(mux),
+				),
+			),
 		),
 	)
 
@@ -67,9 +179,59 @@ func main() {
 		IdleTimeout:  600 * time.Second,
 	}
 
-	logger.Info("Ready to go...")
-	if err := server.ListenAndServe(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Ready to go...")
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		logger.Error("Startup error", "error", err)
+	case <-ctx.Done():
+		// a second signal kills the process right away
+		stop()
+		shutdown(&server, db)
+	}
+}
+
+// traceFlushTimeout limits exporting the buffered spans on shutdown.
+const traceFlushTimeout = 5 * time.Second
+
+// shutdown stops accepting connections, waits for in-flight requests up
+// to config.Conf.ShutdownTimeout, flushes the traces and then closes the
+// storage.
+func shutdown(server *http.Server, db Storage) {
+	logger.Info("Shutting down, draining requests", "timeout", config.Conf.ShutdownTimeout.String())
+	ctx, cancel := context.WithTimeout(context.Background(), config.Conf.ShutdownTimeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		logger.Warn("Drain timeout exceeded, closing remaining connections")
+		err = server.Close()
+	}
+	if err != nil {
+		logger.Error("Server shutdown error", "error", err)
+	} else {
+		logger.Info("Server stopped")
+	}
+
+	// the drain may have used up ctx, the traces get their own time
+	traceCtx, traceCancel := context.WithTimeout(context.Background(), traceFlushTimeout)
+	defer traceCancel()
+	if err := tracing.Shutdown(traceCtx); err != nil {
+		logger.Error("Tracing shutdown error", "error", err)
+	} else {
+		logger.Info("Traces flushed")
+	}
+
+	if err := db.Close(); err != nil {
+		logger.Error("Storage close error", "error", err)
 		return
 	}
+	logger.Info("Storage closed")
 }
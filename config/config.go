@@ -3,9 +3,14 @@ package config
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/mail"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,8 +22,40 @@ const (
 	Production
 )
 
+type StorageDriverEnum int
+
+const (
+	Postgres StorageDriverEnum = iota
+	Sqlite
+	Memory
+)
+
+type TracingExporterEnum int
+
+const (
+	TracingNone TracingExporterEnum = iota
+	TracingStdout
+	TracingFile
+	TracingOtlp
+)
+
+type LogFormatEnum int
+
+const (
+	LogJson LogFormatEnum = iota
+	LogText
+)
+
+type LogOutputEnum int
+
+const (
+	LogStdout LogOutputEnum = iota
+	LogFile
+)
+
 type Config struct {
 	Env                    EnvEnum
+	StorageDriver          StorageDriverEnum
 	Host                   string
 	Port                   int
 	PostgresHost           string
@@ -28,7 +65,23 @@ type Config struct {
 	PostgresDatabase       string
 	PGAdminDefaultEmail    string
 	PGAdminDefaultPassword string
+	SqlitePath             string
 	MigrationPath          string
+	JwtSecret              string
+	JwtTTL                 time.Duration
+	ShutdownTimeout        time.Duration
+	TracingExporter        TracingExporterEnum
+	TracingFile            string
+	TracingOtlpEndpoint    string
+	LogLevel               slog.Level
+	LogFormat              LogFormatEnum
+	LogOutputs             []LogOutputEnum
+	LogFile                string
+	LogFileMaxSize         int
+	LogFileMaxBackups      int
+	DefaultLanguage        string
+	IdempotencyTTL         time.Duration
+	AdminEmail             string
 }
 
 var Conf Config
@@ -73,6 +126,38 @@ func getEnvInt(env string, min, max int) (int, error) {
 	return valInt, nil
 }
 
+func getEnvIntDefault(env string, min, max, def int) (int, error) {
+	if _, have := os.LookupEnv(env); !have {
+		return def, nil
+	}
+	return getEnvInt(env, min, max)
+}
+
+func getEnvDuration(env string, min, max time.Duration) (time.Duration, error) {
+	val, err := getEnv(env)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration like 1h30m", env)
+	}
+	if d < min {
+		return 0, fmt.Errorf("%s must be bigger than %s", env, min)
+	}
+	if d > max {
+		return 0, fmt.Errorf("%s must be smaller than %s", env, max)
+	}
+	return d, nil
+}
+
+func getEnvDurationDefault(env string, min, max, def time.Duration) (time.Duration, error) {
+	if _, have := os.LookupEnv(env); !have {
+		return def, nil
+	}
+	return getEnvDuration(env, min, max)
+}
+
 func getEnvEnum[T any](env string, constraints map[string]T) (*T, error) {
 	val, err := getEnv(env)
 	if err != nil {
@@ -85,6 +170,33 @@ func getEnvEnum[T any](env string, constraints map[string]T) (*T, error) {
 	}
 }
 
+func getEnvEnumDefault[T any](env string, constraints map[string]T, def T) (*T, error) {
+	if _, have := os.LookupEnv(env); !have {
+		return &def, nil
+	}
+	return getEnvEnum(env, constraints)
+}
+
+// getEnvEnumListDefault parses a comma separated list of values from
+// constraints, repeated values are kept once.
+func getEnvEnumListDefault[T comparable](env string, constraints map[string]T, def []T) ([]T, error) {
+	val, have := os.LookupEnv(env)
+	if !have {
+		return def, nil
+	}
+	list := make([]T, 0)
+	for item := range strings.SplitSeq(val, ",") {
+		t, have := constraints[strings.TrimSpace(item)]
+		if !have {
+			return nil, fmt.Errorf("%s must be a comma separated list of %v", env, constraints)
+		}
+		if !slices.Contains(list, t) {
+			list = append(list, t)
+		}
+	}
+	return list, nil
+}
+
 func Initialize() error {
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -97,12 +209,16 @@ func Initialize() error {
 		errs = append(errs, err)
 	}
 
-	postgresPort, err := getEnvInt("POSTGRES_PORT", 1000, 100000)
+	env, err := getEnvEnum("ENV", map[string]EnvEnum{"development": Development, "production": Production})
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	env, err := getEnvEnum("ENV", map[string]EnvEnum{"development": Development, "production": Production})
+	storageDriver, err := getEnvEnumDefault(
+		"STORAGE_DRIVER",
+		map[string]StorageDriverEnum{"postgres": Postgres, "sqlite": Sqlite, "memory": Memory},
+		Postgres,
+	)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -112,6 +228,162 @@ func Initialize() error {
 		errs = append(errs, err)
 	}
 
+	jwtSecret, err := getEnvCustom("JWT_SECRET", func(s string) error {
+		if len(s) < 32 {
+			return fmt.Errorf("JWT_SECRET must be at least 32 characters long")
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	jwtTTL, err := getEnvDuration("JWT_TTL", time.Minute, 30*24*time.Hour)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	shutdownTimeout, err := getEnvDurationDefault("SHUTDOWN_TIMEOUT", time.Second, 5*time.Minute, 15*time.Second)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	idempotencyTTL, err := getEnvDurationDefault("IDEMPOTENCY_TTL", time.Minute, 7*24*time.Hour, 24*time.Hour)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	tracingExporter, err := getEnvEnumDefault(
+		"TRACING_EXPORTER",
+		map[string]TracingExporterEnum{"none": TracingNone, "stdout": TracingStdout, "file": TracingFile, "otlp": TracingOtlp},
+		TracingNone,
+	)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	logLevel, err := getEnvEnumDefault(
+		"LOG_LEVEL",
+		map[string]slog.Level{"debug": slog.LevelDebug, "info": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError},
+		slog.LevelInfo,
+	)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	logFormat, err := getEnvEnumDefault(
+		"LOG_FORMAT",
+		map[string]LogFormatEnum{"json": LogJson, "text": LogText},
+		LogJson,
+	)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	logOutputs, err := getEnvEnumListDefault(
+		"LOG_OUTPUT",
+		map[string]LogOutputEnum{"stdout": LogStdout, "file": LogFile},
+		[]LogOutputEnum{LogStdout},
+	)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	defaultLanguage, err := getEnvEnumDefault(
+		"DEFAULT_LANGUAGE",
+		map[string]string{"ru": "ru", "en": "en"},
+		"ru",
+	)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	adminEmail := ""
+	if _, have := os.LookupEnv("ADMIN_EMAIL"); have {
+		adminEmail, err = getEnvCustom("ADMIN_EMAIL", func(s string) error {
+			if _, err := mail.ParseAddress(s); err != nil {
+				return fmt.Errorf("ADMIN_EMAIL must be a valid email address")
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	conf := Config{
+		Host:            host,
+		Port:            port,
+		JwtSecret:       jwtSecret,
+		JwtTTL:          jwtTTL,
+		ShutdownTimeout: shutdownTimeout,
+		IdempotencyTTL:  idempotencyTTL,
+		AdminEmail:      strings.ToLower(strings.TrimSpace(adminEmail)),
+		LogOutputs:      logOutputs,
+	}
+
+	if slices.Contains(logOutputs, LogFile) {
+		errs = append(errs, initializeLogFile(&conf)...)
+	}
+
+	if tracingExporter != nil && *tracingExporter == TracingFile {
+		tracingFile, err := getEnv("TRACING_FILE")
+		if err != nil {
+			errs = append(errs, err)
+		}
+		conf.TracingFile = tracingFile
+	}
+
+	if tracingExporter != nil && *tracingExporter == TracingOtlp {
+		tracingOtlpEndpoint, err := getEnvCustom("TRACING_OTLP_ENDPOINT", func(s string) error {
+			u, err := url.Parse(s)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("TRACING_OTLP_ENDPOINT must be an http or https url")
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+		// the exporter posts to the url as is, a bare collector address
+		// gets the standard path
+		if u, err := url.Parse(tracingOtlpEndpoint); err == nil && strings.Trim(u.Path, "/") == "" {
+			tracingOtlpEndpoint = u.JoinPath("v1", "traces").String()
+		}
+		conf.TracingOtlpEndpoint = tracingOtlpEndpoint
+	}
+
+	if storageDriver != nil {
+		switch *storageDriver {
+		case Postgres:
+			errs = append(errs, initializePostgres(&conf)...)
+		case Sqlite:
+			errs = append(errs, initializeSqlite(&conf)...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	conf.Env = *env
+	conf.StorageDriver = *storageDriver
+	conf.TracingExporter = *tracingExporter
+	conf.LogLevel = *logLevel
+	conf.LogFormat = *logFormat
+	conf.DefaultLanguage = *defaultLanguage
+	Conf = conf
+
+	return nil
+}
+
+func initializePostgres(conf *Config) []error {
+	errs := make([]error, 0)
+	postgresPort, err := getEnvInt("POSTGRES_PORT", 1000, 100000)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
 	postgresHost, err := getEnv("POSTGRES_HOST")
 	if err != nil {
 		errs = append(errs, err)
@@ -152,23 +424,53 @@ func Initialize() error {
 		errs = append(errs, err)
 	}
 
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+	conf.PostgresHost = postgresHost
+	conf.PostgresPort = postgresPort
+	conf.PostgresUser = postgresUser
+	conf.PostgresPassword = postgresPassword
+	conf.PostgresDatabase = postgresDB
+	conf.PGAdminDefaultPassword = pgAdminDefaulPassword
+	conf.PGAdminDefaultEmail = pgAdminDefaultEmail
+	conf.MigrationPath = migrationPath
+	return errs
+}
+
+func initializeLogFile(conf *Config) []error {
+	errs := make([]error, 0)
+	logFile, err := getEnv("LOG_FILE")
+	if err != nil {
+		errs = append(errs, err)
 	}
 
-	Conf = Config{
-		Env:                    *env,
-		Host:                   host,
-		Port:                   port,
-		PostgresHost:           postgresHost,
-		PostgresPort:           postgresPort,
-		PostgresUser:           postgresUser,
-		PostgresPassword:       postgresPassword,
-		PostgresDatabase:       postgresDB,
-		PGAdminDefaultPassword: pgAdminDefaulPassword,
-		PGAdminDefaultEmail:    pgAdminDefaultEmail,
-		MigrationPath:          migrationPath,
+	maxSize, err := getEnvIntDefault("LOG_FILE_MAX_SIZE", 1, 10000, 100)
+	if err != nil {
+		errs = append(errs, err)
 	}
 
-	return nil
+	maxBackups, err := getEnvIntDefault("LOG_FILE_MAX_BACKUPS", 0, 100, 5)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	conf.LogFile = logFile
+	conf.LogFileMaxSize = maxSize
+	conf.LogFileMaxBackups = maxBackups
+	return errs
+}
+
+func initializeSqlite(conf *Config) []error {
+	errs := make([]error, 0)
+	sqlitePath, err := getEnv("SQLITE_PATH")
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	migrationPath, err := getEnv("MIGRATION_PATH")
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	conf.SqlitePath = sqlitePath
+	conf.MigrationPath = migrationPath
+	return errs
 }
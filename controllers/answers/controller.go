@@ -6,10 +6,12 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/authz"
 	"github.com/gengeo7/highlitent/middleware"
 	answersService "github.com/gengeo7/highlitent/services/answers"
 	answersStorage "github.com/gengeo7/highlitent/storage/answers"
+	idempotencyStorage "github.com/gengeo7/highlitent/storage/idempotency"
 	"github.com/gengeo7/highlitent/types/answers"
 	"github.com/gengeo7/highlitent/utils"
 )
@@ -17,11 +19,17 @@ import (
 const BaseRoute string = "/answers"
 
 type AnswersController struct {
-	Storage answersStorage.Storage
+	Storage       answersStorage.Storage
+	Authenticator *auth.Authenticator
+	Policy        authz.Policy
+	// Idempotency keeps the responses of creating requests sent with
+	// an Idempotency-Key for IdempotencyTTL
+	Idempotency    idempotencyStorage.Storage
+	IdempotencyTTL time.Duration
 }
 
-func NewAnswersController(storage answersStorage.Storage) *AnswersController {
-	return &AnswersController{Storage: storage}
+func NewAnswersController(storage answersStorage.Storage, idempotency idempotencyStorage.Storage, authenticator *auth.Authenticator, policy authz.Policy, idempotencyTTL time.Duration) *AnswersController {
+	return &AnswersController{Storage: storage, Authenticator: authenticator, Policy: policy, Idempotency: idempotency, IdempotencyTTL: idempotencyTTL}
 }
 
 func (ac *AnswersController) RegisterController(mux *http.ServeMux) {
@@ -29,7 +37,9 @@ func (ac *AnswersController) RegisterController(mux *http.ServeMux) {
 		"POST /questions/{id}/answers",
 		middleware.Chain(
 			http.HandlerFunc(ac.postAnswer),
+			middleware.Auth(ac.Authenticator),
 			middleware.Timeout(5*time.Second),
+			middleware.Idempotency(ac.Idempotency, ac.IdempotencyTTL),
 			middleware.ValidateJson[answers.AnswerDto](),
 		),
 	)
@@ -42,10 +52,68 @@ func (ac *AnswersController) RegisterController(mux *http.ServeMux) {
 		),
 	)
 
+	mux.Handle(
+		fmt.Sprintf("PATCH %s/{id}", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(ac.patchAnswer),
+			middleware.Auth(ac.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[answers.AnswerUpdateDto](),
+		),
+	)
+
 	mux.Handle(
 		fmt.Sprintf("DELETE %s/{id}", BaseRoute),
 		middleware.Chain(
 			http.HandlerFunc(ac.deleteAnswer),
+			middleware.Auth(ac.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("POST %s/{id}/vote", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(ac.voteAnswer),
+			middleware.Auth(ac.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[answers.VoteDto](),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("GET %s/{id}/revisions", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(ac.getAnswerRevisions),
+			middleware.OptionalAuth(ac.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("POST %s/{id}/revisions/{revisionId}/rollback", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(ac.rollbackAnswer),
+			middleware.Auth(ac.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateOptionalJson[answers.RollbackDto](),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("POST %s/{id}/restore", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(ac.restoreAnswer),
+			middleware.Auth(ac.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("DELETE %s/{id}/purge", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(ac.purgeAnswer),
+			middleware.Auth(ac.Authenticator),
 			middleware.Timeout(5*time.Second),
 		),
 	)
@@ -55,7 +123,7 @@ func (ac *AnswersController) getAnswer(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		utils.SendResponse(nil, apierror.NewApiError(http.StatusBadRequest, "некоректный id", nil), w, r)
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
 		return
 	}
 	answer, err := answersService.GetAnswer(r.Context(), ac.Storage, id)
@@ -66,21 +134,133 @@ func (ac *AnswersController) deleteAnswer(w http.ResponseWriter, r *http.Request
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		utils.SendResponse(nil, apierror.NewApiError(http.StatusBadRequest, "некоректный id", nil), w, r)
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
 		return
 	}
-	err = answersService.DeleteAnswer(r.Context(), ac.Storage, id)
+	err = answersService.DeleteAnswer(r.Context(), ac.Storage, ac.Policy, actor, id)
 	utils.SendResponse(nil, err, w, r)
 }
 
+func (ac *AnswersController) patchAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[answers.AnswerUpdateDto](r.Context())
+	answer, err := answersService.UpdateAnswer(r.Context(), ac.Storage, ac.Policy, actor, id, dto)
+	utils.SendResponse(&utils.Response{Data: answer, Status: http.StatusOK}, err, w, r)
+}
+
 func (ac *AnswersController) postAnswer(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		utils.SendResponse(nil, apierror.NewApiError(http.StatusBadRequest, "некоректный id", nil), w, r)
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	userID, ok := middleware.UserIdFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
 		return
 	}
 	dto := middleware.DtoFromContext[answers.AnswerDto](r.Context())
-	answer, err := answersService.CreateAnswer(r.Context(), ac.Storage, dto, id)
+	answer, err := answersService.CreateAnswer(r.Context(), ac.Storage, dto, id, userID)
 	utils.SendResponse(&utils.Response{Data: answer, Status: http.StatusCreated}, err, w, r)
 }
+
+func (ac *AnswersController) voteAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	userID, ok := middleware.UserIdFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[answers.VoteDto](r.Context())
+	answer, err := answersService.VoteAnswer(r.Context(), ac.Storage, id, userID, dto)
+	utils.SendResponse(&utils.Response{Data: answer, Status: http.StatusOK}, err, w, r)
+}
+
+func (ac *AnswersController) getAnswerRevisions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	var actor *authz.Actor
+	if a, ok := middleware.ActorFromContext(r.Context()); ok {
+		actor = &a
+	}
+	revisions, err := answersService.GetAnswerRevisions(r.Context(), ac.Storage, ac.Policy, actor, id)
+	utils.SendResponse(&utils.Response{Data: revisions, Status: http.StatusOK}, err, w, r)
+}
+
+func (ac *AnswersController) rollbackAnswer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	revisionID, err := strconv.Atoi(r.PathValue("revisionId"))
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidRevisionId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[answers.RollbackDto](r.Context())
+	answer, err := answersService.RollbackAnswer(r.Context(), ac.Storage, ac.Policy, actor, id, revisionID, dto)
+	utils.SendResponse(&utils.Response{Data: answer, Status: http.StatusOK}, err, w, r)
+}
+
+func (ac *AnswersController) restoreAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	answer, err := answersService.RestoreAnswer(r.Context(), ac.Storage, ac.Policy, actor, id)
+	utils.SendResponse(&utils.Response{Data: answer, Status: http.StatusOK}, err, w, r)
+}
+
+func (ac *AnswersController) purgeAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	err = answersService.PurgeAnswer(r.Context(), ac.Storage, ac.Policy, actor, id)
+	utils.SendResponse(nil, err, w, r)
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/middleware"
+	usersService "github.com/gengeo7/highlitent/services/users"
+	usersStorage "github.com/gengeo7/highlitent/storage/users"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+const BaseRoute string = "/auth"
+
+type AuthController struct {
+	Storage usersStorage.Storage
+	Tokens  *auth.TokenManager
+}
+
+func NewAuthController(storage usersStorage.Storage, tokens *auth.TokenManager) *AuthController {
+	return &AuthController{Storage: storage, Tokens: tokens}
+}
+
+func (ac *AuthController) RegisterController(mux *http.ServeMux) {
+	mux.Handle(
+		fmt.Sprintf("POST %s/login", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(ac.login),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[users.LoginDto](),
+		),
+	)
+}
+
+func (ac *AuthController) login(w http.ResponseWriter, r *http.Request) {
+	dto := middleware.DtoFromContext[users.LoginDto](r.Context())
+	token, err := usersService.Login(r.Context(), ac.Storage, ac.Tokens, dto)
+	utils.SendResponse(&utils.Response{Data: token, Status: http.StatusOK}, err, w, r)
+}
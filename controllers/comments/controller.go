@@ -0,0 +1,73 @@
+package comments
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/middleware"
+	commentsService "github.com/gengeo7/highlitent/services/comments"
+	commentsStorage "github.com/gengeo7/highlitent/storage/comments"
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+const BaseRoute string = "/answers/{id}/comments"
+
+type CommentsController struct {
+	Storage       commentsStorage.Storage
+	Authenticator *auth.Authenticator
+}
+
+func NewCommentsController(storage commentsStorage.Storage, authenticator *auth.Authenticator) *CommentsController {
+	return &CommentsController{Storage: storage, Authenticator: authenticator}
+}
+
+func (cc *CommentsController) RegisterController(mux *http.ServeMux) {
+	mux.Handle(
+		"POST "+BaseRoute,
+		middleware.Chain(
+			http.HandlerFunc(cc.postComment),
+			middleware.Auth(cc.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[comments.CommentDto](),
+		),
+	)
+
+	mux.Handle(
+		"GET "+BaseRoute,
+		middleware.Chain(
+			http.HandlerFunc(cc.getComments),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+}
+
+func (cc *CommentsController) postComment(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	userID, ok := middleware.UserIdFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[comments.CommentDto](r.Context())
+	comment, err := commentsService.CreateComment(r.Context(), cc.Storage, dto, id, userID)
+	utils.SendResponse(&utils.Response{Data: comment, Status: http.StatusCreated}, err, w, r)
+}
+
+func (cc *CommentsController) getComments(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	tree, err := commentsService.GetComments(r.Context(), cc.Storage, id)
+	utils.SendResponse(&utils.Response{Data: tree, Status: http.StatusOK}, err, w, r)
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/middleware"
+	healthService "github.com/gengeo7/highlitent/services/health"
+	healthStorage "github.com/gengeo7/highlitent/storage/health"
+	"github.com/gengeo7/highlitent/types/health"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+type HealthController struct {
+	Storage healthStorage.Storage
+}
+
+func NewHealthController(storage healthStorage.Storage) *HealthController {
+	return &HealthController{Storage: storage}
+}
+
+func (hc *HealthController) RegisterController(mux *http.ServeMux) {
+	mux.Handle("GET /healthz", http.HandlerFunc(hc.live))
+
+	mux.Handle(
+		"GET /readyz",
+		middleware.Chain(
+			http.HandlerFunc(hc.ready),
+			middleware.Timeout(2*time.Second),
+		),
+	)
+}
+
+func (hc *HealthController) live(w http.ResponseWriter, r *http.Request) {
+	utils.SendResponse(&utils.Response{Data: healthService.Live(), Status: http.StatusOK}, nil, w, r)
+}
+
+func (hc *HealthController) ready(w http.ResponseWriter, r *http.Request) {
+	report := healthService.Ready(r.Context(), hc.Storage)
+	status := http.StatusOK
+	if report.Status != health.StatusOk {
+		status = http.StatusServiceUnavailable
+	}
+	utils.SendResponse(&utils.Response{Data: report, Status: status}, nil, w, r)
+}
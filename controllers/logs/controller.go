@@ -0,0 +1,67 @@
+package logs
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/middleware"
+	logsService "github.com/gengeo7/highlitent/services/logs"
+	"github.com/gengeo7/highlitent/types/logs"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+const BaseRoute string = "/admin/log-level"
+
+type LogsController struct {
+	Authenticator *auth.Authenticator
+	Policy        authz.Policy
+}
+
+func NewLogsController(authenticator *auth.Authenticator, policy authz.Policy) *LogsController {
+	return &LogsController{Authenticator: authenticator, Policy: policy}
+}
+
+func (lc *LogsController) RegisterController(mux *http.ServeMux) {
+	mux.Handle(
+		fmt.Sprintf("GET %s", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(lc.getLevel),
+			middleware.Auth(lc.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("PUT %s", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(lc.putLevel),
+			middleware.Auth(lc.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[logs.LevelDto](),
+		),
+	)
+}
+
+func (lc *LogsController) getLevel(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	level, err := logsService.GetLevel(r.Context(), lc.Policy, actor)
+	utils.SendResponse(&utils.Response{Data: level, Status: http.StatusOK}, err, w, r)
+}
+
+func (lc *LogsController) putLevel(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[logs.LevelDto](r.Context())
+	level, err := logsService.SetLevel(r.Context(), lc.Policy, actor, dto)
+	utils.SendResponse(&utils.Response{Data: level, Status: http.StatusOK}, err, w, r)
+}
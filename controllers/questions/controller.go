@@ -6,22 +6,33 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/authz"
 	"github.com/gengeo7/highlitent/middleware"
 	questionsService "github.com/gengeo7/highlitent/services/questions"
+	idempotencyStorage "github.com/gengeo7/highlitent/storage/idempotency"
 	questionsStorage "github.com/gengeo7/highlitent/storage/questions"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/common"
 	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/tags"
 	"github.com/gengeo7/highlitent/utils"
 )
 
 const BaseRoute string = "/questions"
 
 type QuestionsController struct {
-	Storage questionsStorage.Storage
+	Storage       questionsStorage.Storage
+	Authenticator *auth.Authenticator
+	Policy        authz.Policy
+	// Idempotency keeps the responses of creating requests sent with
+	// an Idempotency-Key for IdempotencyTTL
+	Idempotency    idempotencyStorage.Storage
+	IdempotencyTTL time.Duration
 }
 
-func NewQuestionsController(storage questionsStorage.Storage) *QuestionsController {
-	return &QuestionsController{Storage: storage}
+func NewQuestionsController(storage questionsStorage.Storage, idempotency idempotencyStorage.Storage, authenticator *auth.Authenticator, policy authz.Policy, idempotencyTTL time.Duration) *QuestionsController {
+	return &QuestionsController{Storage: storage, Authenticator: authenticator, Policy: policy, Idempotency: idempotency, IdempotencyTTL: idempotencyTTL}
 }
 
 func (qc *QuestionsController) RegisterController(mux *http.ServeMux) {
@@ -37,7 +48,9 @@ func (qc *QuestionsController) RegisterController(mux *http.ServeMux) {
 		fmt.Sprintf("POST %s", BaseRoute),
 		middleware.Chain(
 			http.HandlerFunc(qc.newQuestion),
+			middleware.Auth(qc.Authenticator),
 			middleware.Timeout(5*time.Second),
+			middleware.Idempotency(qc.Idempotency, qc.IdempotencyTTL),
 			middleware.ValidateJson[questions.QuestionDto](),
 		),
 	)
@@ -50,23 +63,105 @@ func (qc *QuestionsController) RegisterController(mux *http.ServeMux) {
 		),
 	)
 
+	mux.Handle(
+		fmt.Sprintf("PATCH %s/{id}", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(qc.patchQuestion),
+			middleware.Auth(qc.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[questions.QuestionUpdateDto](),
+		),
+	)
+
 	mux.Handle(
 		fmt.Sprintf("DELETE %s/{id}", BaseRoute),
 		middleware.Chain(
 			http.HandlerFunc(qc.deleteQuestion),
+			middleware.Auth(qc.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("POST %s/{id}/accept/{answerId}", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(qc.acceptAnswer),
+			middleware.Auth(qc.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("DELETE %s/{id}/accept", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(qc.unacceptAnswer),
+			middleware.Auth(qc.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("GET %s/{id}/revisions", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(qc.getQuestionRevisions),
+			middleware.OptionalAuth(qc.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("POST %s/{id}/revisions/{revisionId}/rollback", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(qc.rollbackQuestion),
+			middleware.Auth(qc.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateOptionalJson[questions.RollbackDto](),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("POST %s/{id}/restore", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(qc.restoreQuestion),
+			middleware.Auth(qc.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("DELETE %s/{id}/purge", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(qc.purgeQuestion),
+			middleware.Auth(qc.Authenticator),
 			middleware.Timeout(5*time.Second),
 		),
 	)
 }
 
 func (qc *QuestionsController) getAllQuestions(w http.ResponseWriter, r *http.Request) {
-	questions, err := questionsService.GetAllQuestions(r.Context(), qc.Storage)
-	utils.SendResponse(&utils.Response{Data: questions, Status: http.StatusOK}, err, w, r)
+	parser := utils.NewQueryParser(r)
+	query := &questions.QuestionsQuery{
+		Limit:  parser.Int("limit", questions.DefaultLimit, 1, questions.MaxLimit),
+		Cursor: parser.Cursor("cursor"),
+		Sort:   parser.Order("sort", common.OrderDesc, common.OrderDesc, common.OrderAsc),
+		Tags:   parser.Strings("tag", tags.MaxPerQuestion, tags.IsValidName),
+	}
+	if err := parser.Err(); err != nil {
+		utils.SendResponse(nil, err, w, r)
+		return
+	}
+	page, err := questionsService.GetAllQuestions(r.Context(), qc.Storage, query)
+	utils.SendResponse(&utils.Response{Data: page, Status: http.StatusOK}, err, w, r)
 }
 
 func (qc *QuestionsController) newQuestion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIdFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
 	dto := middleware.DtoFromContext[questions.QuestionDto](r.Context())
-	question, err := questionsService.CreateQuestion(r.Context(), qc.Storage, dto)
+	question, err := questionsService.CreateQuestion(r.Context(), qc.Storage, dto, userID)
 	utils.SendResponse(&utils.Response{Data: question, Status: http.StatusCreated}, err, w, r)
 }
 
@@ -74,20 +169,156 @@ func (qc *QuestionsController) getQuestionWithAnswers(w http.ResponseWriter, r *
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		utils.SendResponse(nil, apierror.NewApiError(http.StatusBadRequest, "некоректный id", nil), w, r)
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
 		return
 	}
-	questionWithAnswers, err := questionsService.GetQuestionWithAnswers(r.Context(), qc.Storage, id)
+	parser := utils.NewQueryParser(r)
+	query := &answers.AnswersQuery{
+		Limit:  parser.Int("answersLimit", answers.DefaultLimit, 1, answers.MaxLimit),
+		Cursor: parser.Cursor("answersCursor"),
+		Order:  parser.Order("answersOrder", common.OrderAsc, common.OrderAsc, common.OrderDesc, answers.OrderScore),
+	}
+	if err := parser.Err(); err != nil {
+		utils.SendResponse(nil, err, w, r)
+		return
+	}
+	questionWithAnswers, err := questionsService.GetQuestionWithAnswers(r.Context(), qc.Storage, id, query)
 	utils.SendResponse(&utils.Response{Data: questionWithAnswers, Status: http.StatusOK}, err, w, r)
 }
 
+func (qc *QuestionsController) patchQuestion(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[questions.QuestionUpdateDto](r.Context())
+	question, err := questionsService.UpdateQuestion(r.Context(), qc.Storage, qc.Policy, actor, id, dto)
+	utils.SendResponse(&utils.Response{Data: question, Status: http.StatusOK}, err, w, r)
+}
+
 func (qc *QuestionsController) deleteQuestion(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		utils.SendResponse(nil, apierror.NewApiError(http.StatusBadRequest, "некоректный id", nil), w, r)
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	err = questionsService.DeleteQuestion(r.Context(), qc.Storage, qc.Policy, actor, id)
+	utils.SendResponse(nil, err, w, r)
+}
+
+func (qc *QuestionsController) acceptAnswer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	answerID, err := strconv.Atoi(r.PathValue("answerId"))
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidAnswerId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	question, err := questionsService.AcceptAnswer(r.Context(), qc.Storage, qc.Policy, actor, id, answerID)
+	utils.SendResponse(&utils.Response{Data: question, Status: http.StatusOK}, err, w, r)
+}
+
+func (qc *QuestionsController) unacceptAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	question, err := questionsService.UnacceptAnswer(r.Context(), qc.Storage, qc.Policy, actor, id)
+	utils.SendResponse(&utils.Response{Data: question, Status: http.StatusOK}, err, w, r)
+}
+
+func (qc *QuestionsController) getQuestionRevisions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	var actor *authz.Actor
+	if a, ok := middleware.ActorFromContext(r.Context()); ok {
+		actor = &a
+	}
+	revisions, err := questionsService.GetQuestionRevisions(r.Context(), qc.Storage, qc.Policy, actor, id)
+	utils.SendResponse(&utils.Response{Data: revisions, Status: http.StatusOK}, err, w, r)
+}
+
+func (qc *QuestionsController) rollbackQuestion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	revisionID, err := strconv.Atoi(r.PathValue("revisionId"))
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidRevisionId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[questions.RollbackDto](r.Context())
+	question, err := questionsService.RollbackQuestion(r.Context(), qc.Storage, qc.Policy, actor, id, revisionID, dto)
+	utils.SendResponse(&utils.Response{Data: question, Status: http.StatusOK}, err, w, r)
+}
+
+func (qc *QuestionsController) restoreQuestion(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	question, err := questionsService.RestoreQuestion(r.Context(), qc.Storage, qc.Policy, actor, id)
+	utils.SendResponse(&utils.Response{Data: question, Status: http.StatusOK}, err, w, r)
+}
+
+func (qc *QuestionsController) purgeQuestion(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
 		return
 	}
-	err = questionsService.DeleteQuestion(r.Context(), qc.Storage, id)
+	err = questionsService.PurgeQuestion(r.Context(), qc.Storage, qc.Policy, actor, id)
 	utils.SendResponse(nil, err, w, r)
 }
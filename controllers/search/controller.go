@@ -0,0 +1,49 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/middleware"
+	searchService "github.com/gengeo7/highlitent/services/search"
+	searchStorage "github.com/gengeo7/highlitent/storage/search"
+	"github.com/gengeo7/highlitent/types/search"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+const BaseRoute string = "/search"
+
+type SearchController struct {
+	Storage searchStorage.Storage
+}
+
+func NewSearchController(storage searchStorage.Storage) *SearchController {
+	return &SearchController{Storage: storage}
+}
+
+func (sc *SearchController) RegisterController(mux *http.ServeMux) {
+	mux.Handle(
+		fmt.Sprintf("GET %s", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(sc.search),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+}
+
+func (sc *SearchController) search(w http.ResponseWriter, r *http.Request) {
+	parser := utils.NewQueryParser(r)
+	query := &search.SearchQuery{
+		Text:   parser.String("q", true, search.MaxTextLength),
+		Limit:  parser.Int("limit", search.DefaultLimit, 1, search.MaxLimit),
+		Offset: parser.Int("offset", 0, 0, math.MaxInt32),
+	}
+	if err := parser.Err(); err != nil {
+		utils.SendResponse(nil, err, w, r)
+		return
+	}
+	page, err := searchService.Search(r.Context(), sc.Storage, query)
+	utils.SendResponse(&utils.Response{Data: page, Status: http.StatusOK}, err, w, r)
+}
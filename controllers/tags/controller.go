@@ -0,0 +1,48 @@
+package tags
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/middleware"
+	tagsService "github.com/gengeo7/highlitent/services/tags"
+	tagsStorage "github.com/gengeo7/highlitent/storage/tags"
+	"github.com/gengeo7/highlitent/types/tags"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+const BaseRoute string = "/tags"
+
+type TagsController struct {
+	Storage tagsStorage.Storage
+}
+
+func NewTagsController(storage tagsStorage.Storage) *TagsController {
+	return &TagsController{Storage: storage}
+}
+
+func (tc *TagsController) RegisterController(mux *http.ServeMux) {
+	mux.Handle(
+		fmt.Sprintf("GET %s", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(tc.getTags),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+}
+
+func (tc *TagsController) getTags(w http.ResponseWriter, r *http.Request) {
+	parser := utils.NewQueryParser(r)
+	query := &tags.TagsQuery{
+		Limit:  parser.Int("limit", tags.DefaultLimit, 1, tags.MaxLimit),
+		Offset: parser.Int("offset", 0, 0, math.MaxInt32),
+	}
+	if err := parser.Err(); err != nil {
+		utils.SendResponse(nil, err, w, r)
+		return
+	}
+	page, err := tagsService.GetTags(r.Context(), tc.Storage, query)
+	utils.SendResponse(&utils.Response{Data: page, Status: http.StatusOK}, err, w, r)
+}
@@ -0,0 +1,120 @@
+package users
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/middleware"
+	usersService "github.com/gengeo7/highlitent/services/users"
+	usersStorage "github.com/gengeo7/highlitent/storage/users"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/uuid"
+)
+
+const BaseRoute string = "/users"
+
+type UsersController struct {
+	Storage       usersStorage.Storage
+	Authenticator *auth.Authenticator
+	Policy        authz.Policy
+	AdminEmail    string
+}
+
+func NewUsersController(storage usersStorage.Storage, authenticator *auth.Authenticator, policy authz.Policy, adminEmail string) *UsersController {
+	return &UsersController{Storage: storage, Authenticator: authenticator, Policy: policy, AdminEmail: adminEmail}
+}
+
+func (uc *UsersController) RegisterController(mux *http.ServeMux) {
+	mux.Handle(
+		fmt.Sprintf("POST %s", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(uc.registerUser),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[users.UserDto](),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("GET %s", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(uc.getUsers),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("GET %s/{id}", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(uc.getUser),
+			middleware.OptionalAuth(uc.Authenticator),
+			middleware.Timeout(5*time.Second),
+		),
+	)
+
+	mux.Handle(
+		fmt.Sprintf("PUT %s/{id}/role", BaseRoute),
+		middleware.Chain(
+			http.HandlerFunc(uc.putRole),
+			middleware.Auth(uc.Authenticator),
+			middleware.Timeout(5*time.Second),
+			middleware.ValidateJson[users.RoleDto](),
+		),
+	)
+}
+
+func (uc *UsersController) registerUser(w http.ResponseWriter, r *http.Request) {
+	dto := middleware.DtoFromContext[users.UserDto](r.Context())
+	user, err := usersService.RegisterUser(r.Context(), uc.Storage, dto, uc.AdminEmail)
+	utils.SendResponse(&utils.Response{Data: user, Status: http.StatusCreated}, err, w, r)
+}
+
+func (uc *UsersController) getUsers(w http.ResponseWriter, r *http.Request) {
+	parser := utils.NewQueryParser(r)
+	query := &users.UsersQuery{
+		Limit:  parser.Int("limit", users.DefaultLimit, 1, users.MaxLimit),
+		Offset: parser.Int("offset", 0, 0, math.MaxInt32),
+	}
+	if err := parser.Err(); err != nil {
+		utils.SendResponse(nil, err, w, r)
+		return
+	}
+	page, err := usersService.GetUsers(r.Context(), uc.Storage, query)
+	utils.SendResponse(&utils.Response{Data: page, Status: http.StatusOK}, err, w, r)
+}
+
+func (uc *UsersController) getUser(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	var actor *authz.Actor
+	if a, ok := middleware.ActorFromContext(r.Context()); ok {
+		actor = &a
+	}
+	user, err := usersService.GetUser(r.Context(), uc.Storage, uc.Policy, actor, id)
+	utils.SendResponse(&utils.Response{Data: user, Status: http.StatusOK}, err, w, r)
+}
+
+func (uc *UsersController) putRole(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middleware.ActorFromContext(r.Context())
+	if !ok {
+		utils.SendResponse(nil, utils.Unauthorized(nil), w, r)
+		return
+	}
+	idStr := r.PathValue("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.SendResponse(nil, utils.InvalidId(nil), w, r)
+		return
+	}
+	dto := middleware.DtoFromContext[users.RoleDto](r.Context())
+	user, err := usersService.SetRole(r.Context(), uc.Storage, uc.Policy, actor, id, dto)
+	utils.SendResponse(&utils.Response{Data: user, Status: http.StatusOK}, err, w, r)
+}
@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gengeo7/highlitent/config"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"golang.org/x/text/language"
+)
+
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+)
+
+var (
+	supported = []Lang{Russian, English}
+	matcher   = language.NewMatcher([]language.Tag{language.Russian, language.English})
+	universal = newUniversalTranslator()
+)
+
+// fieldKeyPrefix keeps the keys of fieldMessages apart from the keys
+// of the default validator translations in the same translators.
+const fieldKeyPrefix = "field-"
+
+func newUniversalTranslator() *ut.UniversalTranslator {
+	universal := ut.New(en.New(), en.New(), ru.New())
+	for key, translations := range fieldMessages {
+		for lang, text := range translations {
+			trans, _ := universal.GetTranslator(string(lang))
+			if err := trans.Add(fieldKeyPrefix+key, text, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return universal
+}
+
+type langKey struct{}
+
+// Default returns the language set by config.Conf.DefaultLanguage.
+func Default() Lang {
+	if config.Conf.DefaultLanguage == "" {
+		return Russian
+	}
+	return Lang(config.Conf.DefaultLanguage)
+}
+
+// Parse picks the supported language preferred by an Accept-Language
+// header, or Default when none of them is accepted.
+func Parse(acceptLanguage string) Lang {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default()
+	}
+	_, i, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default()
+	}
+	return supported[i]
+}
+
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// FromContext returns the language of the request, or Default outside
+// of a request.
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(langKey{}).(Lang); ok {
+		return lang
+	}
+	return Default()
+}
+
+// Catalog maps a key to its message in every language.
+type Catalog map[string]map[Lang]string
+
+// Message returns the message of key in lang, or in Russian when there
+// is no translation. Unknown keys are returned as is.
+func (c Catalog) Message(lang Lang, key string) string {
+	translations, ok := c[key]
+	if !ok {
+		return key
+	}
+	if msg, ok := translations[lang]; ok {
+		return msg
+	}
+	return translations[Russian]
+}
+
+// Missing returns the keys that have no message in some of the
+// supported languages.
+func (c Catalog) Missing() []string {
+	missing := make([]string, 0)
+	for key, translations := range c {
+		for _, lang := range supported {
+			if translations[lang] == "" {
+				missing = append(missing, fmt.Sprintf("%s (%s)", key, lang))
+			}
+		}
+	}
+	return missing
+}
+
+// Field returns the message of the field error key in lang, params
+// are the name of the field and the parameters of the check.
+func Field(lang Lang, key string, params ...string) string {
+	msg, err := Translator(lang).T(fieldKeyPrefix+key, params...)
+	if err != nil {
+		return key
+	}
+	return msg
+}
+
+// Translator returns the translator of validation errors for lang.
+func Translator(lang Lang) ut.Translator {
+	trans, _ := universal.GetTranslator(string(lang))
+	return trans
+}
@@ -0,0 +1,46 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           Lang
+	}{
+		{name: "empty", acceptLanguage: "", want: Russian},
+		{name: "english", acceptLanguage: "en", want: English},
+		{name: "region", acceptLanguage: "en-GB", want: English},
+		{name: "quality", acceptLanguage: "ru;q=0.5, en;q=0.9", want: English},
+		{name: "first supported", acceptLanguage: "de-DE, en;q=0.8, ru;q=0.7", want: English},
+		{name: "unsupported", acceptLanguage: "de, fr", want: Russian},
+		{name: "malformed", acceptLanguage: ";;q=", want: Russian},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.acceptLanguage); got != tt.want {
+				t.Errorf("Parse(%q) = %v, want: %v", tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for _, key := range fieldMessages.Missing() {
+		t.Errorf("%s has no message", key)
+	}
+}
+
+func TestField(t *testing.T) {
+	if got, want := Field(English, "max-length", "q", "200"), "q must be at most 200 characters long"; got != want {
+		t.Errorf("Field() = %q, want: %q", got, want)
+	}
+	if got, want := Field(Russian, "oneof", "sort", "asc desc"), "sort должно быть одним из [asc desc]"; got != want {
+		t.Errorf("Field() = %q, want: %q", got, want)
+	}
+	if got, want := Field(English, "unknown", "q"), "unknown"; got != want {
+		t.Errorf("Field() = %q, want: %q", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package i18n
+
+// fieldMessages are the messages of field errors not covered by the
+// default validator translations, {0} is the name of the field.
+var fieldMessages = Catalog{
+	"required": {
+		Russian: "{0} обязательное поле",
+		English: "{0} is a required field",
+	},
+	"number": {
+		Russian: "{0} должно быть числом",
+		English: "{0} must be a number",
+	},
+	"min": {
+		Russian: "{0} должно быть не меньше {1}",
+		English: "{0} must be {1} or greater",
+	},
+	"max": {
+		Russian: "{0} должно быть не больше {1}",
+		English: "{0} must be {1} or less",
+	},
+	"max-length": {
+		Russian: "{0} должно быть не длиннее {1} символов",
+		English: "{0} must be at most {1} characters long",
+	},
+	"max-items": {
+		Russian: "{0} должно содержать не больше {1} значений",
+		English: "{0} must contain at most {1} values",
+	},
+	"format": {
+		Russian: "{0} имеет неверный формат",
+		English: "{0} has an invalid format",
+	},
+	"cursor": {
+		Russian: "{0} не является курсором",
+		English: "{0} is not a valid cursor",
+	},
+	"oneof": {
+		Russian: "{0} должно быть одним из [{1}]",
+		English: "{0} must be one of [{1}]",
+	},
+	"tag": {
+		Russian: "{0} должно быть корректным тегом",
+		English: "{0} must be a valid tag",
+	},
+}
@@ -1,16 +1,113 @@
 package logger
 
 import (
+	"context"
+	"io"
 	"log/slog"
 	"os"
+
+	"github.com/gengeo7/highlitent/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	level = new(slog.LevelVar)
+	log   = slog.New(contextHandler{slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})})
+	file  *rotatingFile
 )
 
-var log *slog.Logger
+type loggerKey struct{}
+
+// contextHandler adds the trace id of the span in the context to every
+// record logged with a context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Init sets up the level, format and outputs from config.Conf. The
+// file output is rotated by size.
+func Init() error {
+	level.Set(config.Conf.LogLevel)
 
-func Init() {
-	log = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+	writers := make([]io.Writer, 0, len(config.Conf.LogOutputs))
+	for _, output := range config.Conf.LogOutputs {
+		switch output {
+		case config.LogStdout:
+			writers = append(writers, os.Stdout)
+		case config.LogFile:
+			f, err := openRotatingFile(
+				config.Conf.LogFile,
+				int64(config.Conf.LogFileMaxSize)<<20,
+				config.Conf.LogFileMaxBackups,
+			)
+			if err != nil {
+				return err
+			}
+			file = f
+			writers = append(writers, f)
+		}
+	}
+	out := io.MultiWriter(writers...)
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch config.Conf.LogFormat {
+	case config.LogText:
+		handler = slog.NewTextHandler(out, opts)
+	default:
+		handler = slog.NewJSONHandler(out, opts)
+	}
+	log = slog.New(contextHandler{handler})
+	return nil
+}
+
+// Close closes the log file, nothing should be logged after it.
+func Close() error {
+	if file == nil {
+		return nil
+	}
+	return file.Close()
+}
+
+// Level returns the current minimal level of the records.
+func Level() slog.Level {
+	return level.Level()
+}
+
+// SetLevel changes the minimal level at runtime, loggers already
+// enriched by With follow it as well.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// FromContext returns the logger enriched by With for this context, or
+// the global logger when there is none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return log
+}
+
+// With returns a copy of ctx whose logger adds args to every record,
+// on top of the attributes added earlier in the chain.
+func With(ctx context.Context, args ...any) context.Context {
+	return context.WithValue(ctx, loggerKey{}, FromContext(ctx).With(args...))
 }
 
 func Info(msg string, args ...any) {
@@ -28,3 +125,19 @@ func Error(msg string, args ...any) {
 func Warn(msg string, args ...any) {
 	log.Warn(msg, args...)
 }
+
+func InfoCtx(ctx context.Context, msg string, args ...any) {
+	FromContext(ctx).InfoContext(ctx, msg, args...)
+}
+
+func DebugCtx(ctx context.Context, msg string, args ...any) {
+	FromContext(ctx).DebugContext(ctx, msg, args...)
+}
+
+func ErrorCtx(ctx context.Context, msg string, args ...any) {
+	FromContext(ctx).ErrorContext(ctx, msg, args...)
+}
+
+func WarnCtx(ctx context.Context, msg string, args ...any) {
+	FromContext(ctx).WarnContext(ctx, msg, args...)
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := log
+	log = slog.New(contextHandler{slog.NewJSONHandler(&buf, nil)})
+	t.Cleanup(func() { log = prev })
+	return &buf
+}
+
+func decodeRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode record %q: %v", buf.String(), err)
+	}
+	delete(record, "time")
+	return record
+}
+
+func TestWith(t *testing.T) {
+	buf := captureLog(t)
+
+	ctx := With(context.Background(), "id", "req-1")
+	ctx = With(ctx, "user_id", "user-1")
+	InfoCtx(ctx, "done", "status", 200)
+
+	want := map[string]any{
+		"level":   "INFO",
+		"msg":     "done",
+		"id":      "req-1",
+		"user_id": "user-1",
+		"status":  float64(200),
+	}
+	if diff := cmp.Diff(want, decodeRecord(t, buf)); diff != "" {
+		t.Errorf("record mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithoutContextLogger(t *testing.T) {
+	buf := captureLog(t)
+
+	WarnCtx(context.Background(), "plain")
+
+	want := map[string]any{"level": "WARN", "msg": "plain"}
+	if diff := cmp.Diff(want, decodeRecord(t, buf)); diff != "" {
+		t.Errorf("record mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	buf := captureLog(t)
+
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID := trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ErrorCtx(ctx, "failed")
+
+	want := map[string]any{
+		"level":    "ERROR",
+		"msg":      "failed",
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+	}
+	if diff := cmp.Diff(want, decodeRecord(t, buf)); diff != "" {
+		t.Errorf("record mismatch (-want +got):\n%s", diff)
+	}
+}
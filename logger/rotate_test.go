@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(b)
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("openRotatingFile() failed: %v", err)
+	}
+	defer f.Close()
+
+	for _, record := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(record)); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for p, content := range want {
+		if got := readFile(t, p); got != content {
+			t.Errorf("%s = %q, want: %q", filepath.Base(p), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, stat app.log.3: %v", err)
+	}
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := openRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("openRotatingFile() failed: %v", err)
+	}
+	defer f.Close()
+
+	// the size of the existing file counts towards the limit
+	for _, record := range []string{"new\n", "newer\n"} {
+		if _, err := f.Write([]byte(record)); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+
+	if got := readFile(t, path+".1"); got != "old\nnew\n" {
+		t.Errorf("app.log.1 = %q, want: %q", got, "old\nnew\n")
+	}
+	if got := readFile(t, path); got != "newer\n" {
+		t.Errorf("app.log = %q, want: %q", got, "newer\n")
+	}
+}
+
+func TestRotatingFileRotateFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := openRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("openRotatingFile() failed: %v", err)
+	}
+	defer f.Close()
+
+	// app.log cannot be renamed over a non-empty directory
+	if err := os.MkdirAll(filepath.Join(path+".1", "dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if _, err := f.Write([]byte("second\n")); err == nil {
+		t.Errorf("Write() expected a rotation error")
+	}
+	if got := readFile(t, path); got != "first\nsecond\n" {
+		t.Errorf("app.log = %q, want: %q", got, "first\nsecond\n")
+	}
+
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("third\n")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if got := readFile(t, path+".1"); got != "first\nsecond\n" {
+		t.Errorf("app.log.1 = %q, want: %q", got, "first\nsecond\n")
+	}
+	if got := readFile(t, path); got != "third\n" {
+		t.Errorf("app.log = %q, want: %q", got, "third\n")
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/auth"
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/logger"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/common"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/uuid"
+)
+
+func Auth(authenticator *auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticate(w, r, next, authenticator, true)
+		})
+	}
+}
+
+// OptionalAuth is Auth for routes open to everyone, where the logged in
+// user may see more. Requests without a token pass through without a
+// user in the context, a wrong token is still rejected.
+func OptionalAuth(authenticator *auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticate(w, r, next, authenticator, false)
+		})
+	}
+}
+
+func authenticate(w http.ResponseWriter, r *http.Request, next http.Handler, authenticator *auth.Authenticator, required bool) {
+	header := r.Header.Get("Authorization")
+	if header == "" && !required {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	// the span covers only the token check, it is ended again
+	// right before the handler runs
+	spanCtx, span := tracing.Start(r.Context(), "middleware.Auth")
+	defer span.End()
+
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		apierror.SendError(w, r, utils.Unauthorized(nil))
+		return
+	}
+
+	user, err := authenticator.Authenticate(spanCtx, token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			apierror.SendError(w, r, utils.InvalidToken(nil))
+		} else {
+			apierror.SendError(w, r, utils.TestDbErr(err))
+		}
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), common.UserIdKey{}, user.ID)
+	ctx = context.WithValue(ctx, common.UserRoleKey{}, user.Role)
+	ctx = logger.With(ctx, "user_id", user.ID.String())
+	span.End()
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func UserIdFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(common.UserIdKey{}).(uuid.UUID)
+	return userID, ok
+}
+
+func ActorFromContext(ctx context.Context) (authz.Actor, bool) {
+	userID, ok := UserIdFromContext(ctx)
+	if !ok {
+		return authz.Actor{}, false
+	}
+	role, ok := ctx.Value(common.UserRoleKey{}).(users.Role)
+	if !ok {
+		return authz.Actor{}, false
+	}
+	return authz.Actor{UserID: userID, Role: role}, true
+}
@@ -1,10 +1,24 @@
 package middleware
 
-import "net/http"
+import (
+	"net/http"
 
+	"github.com/gengeo7/highlitent/logger"
+)
+
+// Chain wraps h in the middleware m, the first one runs first. The
+// route matched by the mux is added to the context logger before all
+// of them.
 func Chain(h http.Handler, m ...func(http.Handler) http.Handler) http.Handler {
 	for i := len(m) - 1; i >= 0; i-- {
 		h = m[i](h)
 	}
-	return h
+	return withRoute(h)
+}
+
+func withRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := logger.With(r.Context(), "route", r.Pattern)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/logger"
+	idempotencyService "github.com/gengeo7/highlitent/services/idempotency"
+	idempotencyStorage "github.com/gengeo7/highlitent/storage/idempotency"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotentBody limits the body read into memory to be fingerprinted.
+const maxIdempotentBody = 1 << 20
+
+// bodyRecorder keeps a copy of the response body.
+type bodyRecorder struct {
+	statusRecorder
+	body bytes.Buffer
+}
+
+func (b *bodyRecorder) Write(p []byte) (int, error) {
+	b.body.Write(p)
+	return b.statusRecorder.Write(p)
+}
+
+// Idempotency stores the successful response of a request sent with an
+// Idempotency-Key for ttl and replays it when the user repeats the
+// request with the same key. A key reused with another body or sent
+// again while the first request runs gets 409. It has to go after Auth,
+// keys are kept per user.
+func Idempotency(store idempotencyStorage.Storage, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID, ok := UserIdFromContext(r.Context())
+			if !ok {
+				apierror.SendError(w, r, utils.Unauthorized(nil))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+			r.Body.Close()
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					apierror.SendError(w, r, utils.BodyTooLarge(nil))
+				} else {
+					apierror.SendError(w, r, utils.InvalidBody(nil))
+				}
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := idempotencyService.Fingerprint(r.Method, r.URL.Path, body)
+			stored, err := idempotencyService.Start(r.Context(), store, userID, key, fingerprint, ttl)
+			if err != nil {
+				apierror.SendError(w, r, err)
+				return
+			}
+			if stored != nil {
+				w.Header().Set("Content-type", stored.ContentType)
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(stored.Status)
+				w.Write(stored.Body)
+				return
+			}
+
+			// the response is saved even if the request context is
+			// already done
+			ctx := context.WithoutCancel(r.Context())
+			finished := false
+			defer func() {
+				// the handler panicked, the key must not stay in progress
+				if !finished {
+					if err := idempotencyService.Release(ctx, store, userID, key); err != nil {
+						logger.ErrorCtx(ctx, "idempotency key not released", "error", err)
+					}
+				}
+			}()
+
+			rec := &bodyRecorder{statusRecorder: statusRecorder{ResponseWriter: w}}
+			next.ServeHTTP(rec, r)
+			finished = true
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			err = idempotencyService.Finish(ctx, store, userID, key, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes())
+			if err != nil {
+				logger.ErrorCtx(ctx, "idempotency key not saved", "error", err)
+			}
+		})
+	}
+}
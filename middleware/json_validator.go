@@ -6,19 +6,76 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/i18n"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/tags"
+	"github.com/gengeo7/highlitent/utils"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	ruTranslations "github.com/go-playground/validator/v10/translations/ru"
 )
 
-var validate *validator.Validate = validator.New()
+var validate *validator.Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterValidation("tag", func(fl validator.FieldLevel) bool {
+		return tags.IsValidName(fl.Field().String())
+	})
+	// messages name fields the way the client sent them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	registerTranslations(v, i18n.Russian, ruTranslations.RegisterDefaultTranslations)
+	registerTranslations(v, i18n.English, enTranslations.RegisterDefaultTranslations)
+	return v
+}
+
+func registerTranslations(v *validator.Validate, lang i18n.Lang, register func(*validator.Validate, ut.Translator) error) {
+	trans := i18n.Translator(lang)
+	err := errors.Join(
+		register(v, trans),
+		v.RegisterTranslation("tag", trans,
+			func(ut.Translator) error { return nil },
+			func(_ ut.Translator, fe validator.FieldError) string {
+				return i18n.Field(lang, "tag", fe.Field())
+			},
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+}
 
 type ValidateJsonKey struct{}
 
 func ValidateJson[T any]() func(http.Handler) http.Handler {
+	return validateJson[T](true)
+}
+
+// ValidateOptionalJson is ValidateJson for bodies that may be left
+// out, the zero T is validated and put in the context then.
+func ValidateOptionalJson[T any]() func(http.Handler) http.Handler {
+	return validateJson[T](false)
+}
+
+func validateJson[T any](required bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the span covers only the validation, it is ended again
+			// right before the handler runs
+			_, span := tracing.Start(r.Context(), "middleware.ValidateJson")
+			defer span.End()
+
 			body := r.Body
 			defer body.Close()
 
@@ -27,11 +84,14 @@ func ValidateJson[T any]() func(http.Handler) http.Handler {
 			decoder.DisallowUnknownFields()
 			err := decoder.Decode(&v)
 			if err == io.EOF {
-				apierror.SendError(w, r, apierror.NewApiError(http.StatusBadRequest, "пустое тело запроса", nil))
-				return
+				if required {
+					apierror.SendError(w, r, utils.EmptyBody(nil))
+					return
+				}
+				err = nil
 			}
 			if err != nil {
-				apierror.SendError(w, r, apierror.NewApiError(http.StatusBadRequest, "ошибка чтения тела запроса", nil))
+				apierror.SendError(w, r, utils.InvalidBody(nil))
 				return
 			}
 
@@ -42,21 +102,19 @@ func ValidateJson[T any]() func(http.Handler) http.Handler {
 					apierror.SendError(w, r, err)
 					return
 				}
+				trans := i18n.Translator(i18n.FromContext(r.Context()))
 				validationErrors := make(map[string]string)
 				for _, e := range err.(validator.ValidationErrors) {
-					field := e.Field()
+					field := e.StructField()
 					field = strings.ToLower(string(field[0])) + field[1:]
-					tag := e.Tag()
-					if e.Param() != "" {
-						tag += ": " + e.Param()
-					}
-					validationErrors[field] = tag
+					validationErrors[field] = e.Translate(trans)
 				}
 
-				apierror.SendError(w, r, apierror.NewValidationError("ошибка валидации", validationErrors))
+				apierror.SendError(w, r, apierror.NewValidationError(validationErrors))
 				return
 			}
 
+			span.End()
 			ctx := context.WithValue(r.Context(), ValidateJsonKey{}, v)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -7,13 +7,48 @@ import (
 	"github.com/gengeo7/highlitent/logger"
 	"github.com/gengeo7/highlitent/types/common"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const requestIdHeader = "X-Request-ID"
+
+const maxRequestIdLen = 128
+
+// validRequestId accepts ids of up to maxRequestIdLen letters, digits
+// and "-_.:", anything else could break the logs or the headers.
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Log gives the request an id and logs it. A valid X-Request-ID from
+// the client is kept, otherwise a new UUID is generated, and the id is
+// returned in the X-Request-ID response header. The id, method and
+// path without the query are added to the context logger, and the id
+// is put on the span started by Trace, so the logs and the trace lead
+// to each other. The route is not matched yet here, Chain adds it.
 func Log(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		id := uuid.New().String()
-		logger.Info("new request", "ip", r.RemoteAddr, "route", r.RequestURI, "id", id)
+		id := r.Header.Get(requestIdHeader)
+		if !validRequestId(id) {
+			id = uuid.New().String()
+		}
+		w.Header().Set(requestIdHeader, id)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", id))
 		ctx := context.WithValue(r.Context(), common.RequestIdKey{}, id)
+		ctx = logger.With(ctx, "id", id, "method", r.Method, "path", r.URL.Path)
+		logger.InfoCtx(ctx, "new request", "ip", r.RemoteAddr)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -4,17 +4,19 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/gengeo7/highlitent/apierror"
 	"github.com/gengeo7/highlitent/logger"
+	"github.com/gengeo7/highlitent/utils"
 )
 
 func Recoverer(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Error("PANIC in request",
+				logger.ErrorCtx(r.Context(), "PANIC in request",
 					"error", err,
 					"stacktrace", string(debug.Stack()))
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				apierror.SendError(w, r, utils.UnhandledError(nil))
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -5,21 +5,67 @@ import (
 	"time"
 
 	"github.com/gengeo7/highlitent/logger"
-	"github.com/gengeo7/highlitent/types/common"
+	"github.com/gengeo7/highlitent/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// statusRecorder remembers the status code written by the handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// TimeElapsed logs every request with its status and duration and
+// records them to metrics and to the request span. It has to wrap the
+// mux, the route is known only after the mux has matched the request.
 func TimeElapsed(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
 		defer func() {
 			elapsed := time.Since(start)
-			logger.Info(
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			metrics.ObserveRequest(r.Method, r.Pattern, rec.status, elapsed)
+			span := trace.SpanFromContext(r.Context())
+			if r.Pattern != "" {
+				span.SetName(r.Pattern)
+				span.SetAttributes(attribute.String("http.route", r.Pattern))
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+			if rec.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rec.status))
+			}
+			logger.InfoCtx(
+				r.Context(),
 				"request completed",
+				"route", r.Pattern,
 				"elapsed_ms", elapsed.Milliseconds(),
-				"path", r.URL.Path,
-				"id", r.Context().Value(common.RequestIdKey{}),
+				"status", rec.status,
 			)
 		}()
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(rec, r)
 	})
 }
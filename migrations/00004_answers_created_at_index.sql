@@ -0,0 +1,5 @@
+-- +goose Up
+create index idx_answers_question_id_created_at_id on answers(question_id, created_at, id);
+
+-- +goose Down
+drop index if exists idx_answers_question_id_created_at_id;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- the config has to match searchConfig in storage/gormdb/search.go
+alter table questions add column search_vector tsvector
+    generated always as (to_tsvector('russian', text)) stored;
+create index idx_questions_search_vector on questions using gin(search_vector);
+
+alter table answers add column search_vector tsvector
+    generated always as (to_tsvector('russian', text)) stored;
+create index idx_answers_search_vector on answers using gin(search_vector);
+
+-- +goose Down
+drop index if exists idx_answers_search_vector;
+alter table answers drop column if exists search_vector;
+drop index if exists idx_questions_search_vector;
+alter table questions drop column if exists search_vector;
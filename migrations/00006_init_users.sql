@@ -0,0 +1,19 @@
+-- +goose Up
+create table users (
+    id uuid primary key,
+    name text not null,
+    email text not null,
+    password_hash text not null,
+    created_at timestamp default current_timestamp,
+    updated_at timestamp default current_timestamp
+);
+create unique index idx_users_email on users(email);
+
+alter table questions add column user_id uuid references users(id) on delete set null;
+create index idx_questions_user_id on questions(user_id);
+
+-- +goose Down
+drop index if exists idx_questions_user_id;
+alter table questions drop column if exists user_id;
+drop index if exists idx_users_email;
+drop table if exists users;
@@ -0,0 +1,17 @@
+-- +goose Up
+create table answer_votes (
+    answer_id bigint not null references answers(id) on delete cascade,
+    user_id uuid not null references users(id) on delete cascade,
+    value smallint not null check (value in (-1, 1)),
+    created_at timestamp default current_timestamp,
+    updated_at timestamp default current_timestamp,
+    primary key (answer_id, user_id)
+);
+
+alter table answers add column score integer not null default 0;
+create index idx_answers_question_id_score on answers(question_id, score desc, created_at, id);
+
+-- +goose Down
+drop index if exists idx_answers_question_id_score;
+alter table answers drop column if exists score;
+drop table if exists answer_votes;
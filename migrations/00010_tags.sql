@@ -0,0 +1,20 @@
+-- +goose Up
+create table tags (
+    id bigserial primary key,
+    name text not null,
+    created_at timestamp default current_timestamp
+);
+create unique index idx_tags_name on tags(name);
+
+create table question_tags (
+    question_id bigint not null references questions(id) on delete cascade,
+    tag_id bigint not null references tags(id) on delete cascade,
+    primary key (question_id, tag_id)
+);
+create index idx_question_tags_tag_id on question_tags(tag_id);
+
+-- +goose Down
+drop index if exists idx_question_tags_tag_id;
+drop table if exists question_tags;
+drop index if exists idx_tags_name;
+drop table if exists tags;
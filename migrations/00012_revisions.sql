@@ -0,0 +1,26 @@
+-- +goose Up
+create table question_revisions (
+    id bigserial primary key,
+    question_id bigint not null references questions(id) on delete cascade,
+    text text not null,
+    editor_id uuid references users(id) on delete set null,
+    reason text not null default '',
+    created_at timestamp default current_timestamp
+);
+create index idx_question_revisions_question_id on question_revisions(question_id, created_at);
+
+create table answer_revisions (
+    id bigserial primary key,
+    answer_id bigint not null references answers(id) on delete cascade,
+    text text not null,
+    editor_id uuid references users(id) on delete set null,
+    reason text not null default '',
+    created_at timestamp default current_timestamp
+);
+create index idx_answer_revisions_answer_id on answer_revisions(answer_id, created_at);
+
+-- +goose Down
+drop index if exists idx_answer_revisions_answer_id;
+drop table if exists answer_revisions;
+drop index if exists idx_question_revisions_question_id;
+drop table if exists question_revisions;
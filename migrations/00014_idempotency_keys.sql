@@ -0,0 +1,18 @@
+-- +goose Up
+create table idempotency_keys (
+    user_id uuid not null references users(id) on delete cascade,
+    key text not null,
+    fingerprint text not null,
+    status integer not null default 0,
+    content_type text not null default '',
+    body bytea,
+    created_at timestamp default current_timestamp,
+    expires_at timestamp not null,
+    locked_until timestamp not null,
+    primary key (user_id, key)
+);
+create index idx_idempotency_keys_expires_at on idempotency_keys(expires_at);
+
+-- +goose Down
+drop index if exists idx_idempotency_keys_expires_at;
+drop table if exists idempotency_keys;
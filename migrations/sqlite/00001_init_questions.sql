@@ -0,0 +1,10 @@
+-- +goose Up
+create table questions (
+    id integer primary key autoincrement,
+    text text not null,
+    created_at datetime default current_timestamp,
+    updated_at datetime default current_timestamp
+);
+
+-- +goose Down
+drop table if exists questions;
@@ -0,0 +1,14 @@
+-- +goose Up
+create table answers (
+    id integer primary key autoincrement,
+    question_id integer not null references questions(id) on delete cascade,
+    user_id text not null,
+    text text not null,
+    created_at datetime default current_timestamp,
+    updated_at datetime default current_timestamp
+);
+create index idx_answers_question_id on answers(question_id);
+
+-- +goose Down
+drop index if exists idx_answers_question_id;
+drop table if exists answers;
@@ -0,0 +1,5 @@
+-- +goose Up
+create index idx_questions_created_at_id on questions(created_at, id);
+
+-- +goose Down
+drop index if exists idx_questions_created_at_id;
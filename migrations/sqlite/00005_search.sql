@@ -0,0 +1,58 @@
+-- +goose Up
+create virtual table questions_fts using fts5(
+    text, content='questions', content_rowid='id', tokenize='unicode61'
+);
+insert into questions_fts(rowid, text) select id, text from questions;
+
+-- +goose StatementBegin
+create trigger questions_fts_insert after insert on questions begin
+    insert into questions_fts(rowid, text) values (new.id, new.text);
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger questions_fts_delete after delete on questions begin
+    insert into questions_fts(questions_fts, rowid, text) values ('delete', old.id, old.text);
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger questions_fts_update after update of text on questions begin
+    insert into questions_fts(questions_fts, rowid, text) values ('delete', old.id, old.text);
+    insert into questions_fts(rowid, text) values (new.id, new.text);
+end;
+-- +goose StatementEnd
+
+create virtual table answers_fts using fts5(
+    text, content='answers', content_rowid='id', tokenize='unicode61'
+);
+insert into answers_fts(rowid, text) select id, text from answers;
+
+-- +goose StatementBegin
+create trigger answers_fts_insert after insert on answers begin
+    insert into answers_fts(rowid, text) values (new.id, new.text);
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger answers_fts_delete after delete on answers begin
+    insert into answers_fts(answers_fts, rowid, text) values ('delete', old.id, old.text);
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger answers_fts_update after update of text on answers begin
+    insert into answers_fts(answers_fts, rowid, text) values ('delete', old.id, old.text);
+    insert into answers_fts(rowid, text) values (new.id, new.text);
+end;
+-- +goose StatementEnd
+
+-- +goose Down
+drop trigger if exists answers_fts_update;
+drop trigger if exists answers_fts_delete;
+drop trigger if exists answers_fts_insert;
+drop table if exists answers_fts;
+drop trigger if exists questions_fts_update;
+drop trigger if exists questions_fts_delete;
+drop trigger if exists questions_fts_insert;
+drop table if exists questions_fts;
@@ -0,0 +1,17 @@
+-- +goose Up
+create table answer_votes (
+    answer_id integer not null references answers(id) on delete cascade,
+    user_id text not null references users(id) on delete cascade,
+    value integer not null check (value in (-1, 1)),
+    created_at datetime default current_timestamp,
+    updated_at datetime default current_timestamp,
+    primary key (answer_id, user_id)
+);
+
+alter table answers add column score integer not null default 0;
+create index idx_answers_question_id_score on answers(question_id, score desc, created_at, id);
+
+-- +goose Down
+drop index if exists idx_answers_question_id_score;
+alter table answers drop column score;
+drop table if exists answer_votes;
@@ -0,0 +1,5 @@
+-- +goose Up
+alter table questions add column accepted_answer_id integer references answers(id) on delete set null;
+
+-- +goose Down
+alter table questions drop column accepted_answer_id;
@@ -0,0 +1,15 @@
+-- +goose Up
+create table comments (
+    id integer primary key autoincrement,
+    answer_id integer not null references answers(id) on delete cascade,
+    parent_id integer references comments(id) on delete cascade,
+    user_id text not null references users(id) on delete cascade,
+    text text not null,
+    created_at datetime default current_timestamp,
+    updated_at datetime default current_timestamp
+);
+create index idx_comments_answer_id_created_at_id on comments(answer_id, created_at, id);
+
+-- +goose Down
+drop index if exists idx_comments_answer_id_created_at_id;
+drop table if exists comments;
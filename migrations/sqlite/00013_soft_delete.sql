@@ -0,0 +1,11 @@
+-- +goose Up
+alter table questions add column deleted_at datetime;
+alter table answers add column deleted_at datetime;
+create index idx_questions_deleted_at on questions(deleted_at);
+create index idx_answers_deleted_at on answers(deleted_at);
+
+-- +goose Down
+drop index if exists idx_answers_deleted_at;
+drop index if exists idx_questions_deleted_at;
+alter table answers drop column deleted_at;
+alter table questions drop column deleted_at;
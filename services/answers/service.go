@@ -3,9 +3,13 @@ package answers
 import (
 	"context"
 
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/logger"
 	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/tracing"
 	"github.com/gengeo7/highlitent/types/answers"
 	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/uuid"
 )
 
 type AnswerGetter interface {
@@ -13,14 +17,56 @@ type AnswerGetter interface {
 }
 
 type AnswerCreater interface {
-	AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int) (*answers.Answer, error)
+	AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int, userID uuid.UUID) (*answers.Answer, error)
+}
+
+type AnswerAuthorGetter interface {
+	AnswerAuthor(ctx context.Context, id int) (*uuid.UUID, error)
+}
+
+// AnswerTrashAuthorGetter finds the author of answers in the trash as well.
+type AnswerTrashAuthorGetter interface {
+	AnswerAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error)
+}
+
+type AnswerUpdater interface {
+	AnswerAuthorGetter
+	AnswerUpdate(ctx context.Context, id int, dto *answers.AnswerUpdateDto, editorID uuid.UUID) (*answers.Answer, error)
+}
+
+type AnswerRevisionsGetter interface {
+	AnswerRevisionsGet(ctx context.Context, id int) ([]answers.Revision, error)
+	AnswerRevisionsGetUnscoped(ctx context.Context, id int) ([]answers.Revision, error)
+}
+
+type AnswerRollbacker interface {
+	AnswerUpdater
+	AnswerRevisionGet(ctx context.Context, id int, revisionID int) (*answers.Revision, error)
 }
 
 type AnswerDeleter interface {
+	AnswerAuthorGetter
 	AnswerDelete(ctx context.Context, id int) error
 }
 
+type AnswerRestorer interface {
+	AnswerTrashAuthorGetter
+	AnswerRestore(ctx context.Context, id int) (*answers.Answer, error)
+}
+
+type AnswerPurger interface {
+	AnswerTrashAuthorGetter
+	AnswerPurge(ctx context.Context, id int) error
+}
+
+type AnswerVoter interface {
+	AnswerVote(ctx context.Context, id int, userID uuid.UUID, value int) (*answers.Answer, error)
+}
+
 func GetAnswer(ctx context.Context, answerGetter AnswerGetter, id int) (*answers.Answer, error) {
+	ctx, span := tracing.Start(ctx, "answers.GetAnswer")
+	defer span.End()
+
 	answer, err := answerGetter.AnswerGet(ctx, id)
 	if err != nil {
 		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false})
@@ -28,18 +74,162 @@ func GetAnswer(ctx context.Context, answerGetter AnswerGetter, id int) (*answers
 	return answer, nil
 }
 
-func CreateAnswer(ctx context.Context, answerCreater AnswerCreater, dto *answers.AnswerDto, questionID int) (*answers.Answer, error) {
-	answer, err := answerCreater.AnswerCreate(ctx, dto, questionID)
+func CreateAnswer(ctx context.Context, answerCreater AnswerCreater, dto *answers.AnswerDto, questionID int, userID uuid.UUID) (*answers.Answer, error) {
+	ctx, span := tracing.Start(ctx, "answers.CreateAnswer")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	answer, err := answerCreater.AnswerCreate(ctx, dto, questionID, userID)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false},
+		)
+	}
+	return answer, nil
+}
+
+// authorizeAnswer checks that actor can do action on the answer whose
+// author is returned by author.
+func authorizeAnswer(ctx context.Context, author func(ctx context.Context, id int) (*uuid.UUID, error), policy authz.Policy, actor authz.Actor, action authz.Action, id int) error {
+	authorID, err := author(ctx, id)
 	if err != nil {
-		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false})
+		return utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false})
+	}
+	if !policy.Can(actor, action, authorID) {
+		logger.WarnCtx(ctx, "action forbidden", "action", action, "answer_id", id)
+		return utils.Forbidden(nil)
+	}
+	return nil
+}
+
+func UpdateAnswer(ctx context.Context, answerUpdater AnswerUpdater, policy authz.Policy, actor authz.Actor, id int, dto *answers.AnswerUpdateDto) (*answers.Answer, error) {
+	ctx, span := tracing.Start(ctx, "answers.UpdateAnswer")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	if err := authorizeAnswer(ctx, answerUpdater.AnswerAuthor, policy, actor, authz.ActionEdit, id); err != nil {
+		return nil, err
+	}
+	answer, err := answerUpdater.AnswerUpdate(ctx, id, dto, actor.UserID)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false},
+		)
 	}
 	return answer, nil
 }
 
-func DeleteAnswer(ctx context.Context, answerDeleter AnswerDeleter, id int) error {
+// GetAnswerRevisions returns the history of the answer. The history of
+// a answer in the trash is shown only to actors who can view the
+// trash, actor is nil for anonymous requests.
+func GetAnswerRevisions(ctx context.Context, revisionsGetter AnswerRevisionsGetter, policy authz.Policy, actor *authz.Actor, id int) ([]answers.Revision, error) {
+	ctx, span := tracing.Start(ctx, "answers.GetAnswerRevisions")
+	defer span.End()
+
+	get := revisionsGetter.AnswerRevisionsGet
+	if actor != nil && policy.Can(*actor, authz.ActionViewTrash, nil) {
+		get = revisionsGetter.AnswerRevisionsGetUnscoped
+	}
+	revisions, err := get(ctx, id)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false})
+	}
+	return revisions, nil
+}
+
+// RollbackAnswer brings back the text of the revision as a new edit,
+// so the text it replaces is kept in the history as well. rollback is
+// nil when the request has no body.
+func RollbackAnswer(ctx context.Context, rollbacker AnswerRollbacker, policy authz.Policy, actor authz.Actor, id int, revisionID int, rollback *answers.RollbackDto) (*answers.Answer, error) {
+	ctx, span := tracing.Start(ctx, "answers.RollbackAnswer")
+	defer span.End()
+
+	if err := authorizeAnswer(ctx, rollbacker.AnswerAuthor, policy, actor, authz.ActionEdit, id); err != nil {
+		return nil, err
+	}
+	revision, err := rollbacker.AnswerRevisionGet(ctx, id, revisionID)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrRevisionNotFound, Creator: utils.RevisionNotFound, CheckErr: false})
+	}
+	dto := answers.AnswerUpdateDto{Text: revision.Text}
+	if rollback != nil {
+		dto.Reason = rollback.Reason
+	}
+	answer, err := rollbacker.AnswerUpdate(ctx, id, &dto, actor.UserID)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false},
+		)
+	}
+	logger.InfoCtx(ctx, "answer rolled back", "answer_id", id, "revision_id", revisionID)
+	return answer, nil
+}
+
+func DeleteAnswer(ctx context.Context, answerDeleter AnswerDeleter, policy authz.Policy, actor authz.Actor, id int) error {
+	ctx, span := tracing.Start(ctx, "answers.DeleteAnswer")
+	defer span.End()
+
+	if err := authorizeAnswer(ctx, answerDeleter.AnswerAuthor, policy, actor, authz.ActionDelete, id); err != nil {
+		return err
+	}
 	err := answerDeleter.AnswerDelete(ctx, id)
 	if err != nil {
 		return utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false})
 	}
+	logger.InfoCtx(ctx, "answer moved to trash", "answer_id", id)
 	return nil
 }
+
+func RestoreAnswer(ctx context.Context, answerRestorer AnswerRestorer, policy authz.Policy, actor authz.Actor, id int) (*answers.Answer, error) {
+	ctx, span := tracing.Start(ctx, "answers.RestoreAnswer")
+	defer span.End()
+
+	if err := authorizeAnswer(ctx, answerRestorer.AnswerAuthorUnscoped, policy, actor, authz.ActionRestore, id); err != nil {
+		return nil, err
+	}
+	answer, err := answerRestorer.AnswerRestore(ctx, id)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFoundInTrash, CheckErr: false})
+	}
+	logger.InfoCtx(ctx, "answer restored", "answer_id", id)
+	return answer, nil
+}
+
+func PurgeAnswer(ctx context.Context, answerPurger AnswerPurger, policy authz.Policy, actor authz.Actor, id int) error {
+	ctx, span := tracing.Start(ctx, "answers.PurgeAnswer")
+	defer span.End()
+
+	if err := authorizeAnswer(ctx, answerPurger.AnswerAuthorUnscoped, policy, actor, authz.ActionPurge, id); err != nil {
+		return err
+	}
+	err := answerPurger.AnswerPurge(ctx, id)
+	if err != nil {
+		return utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false})
+	}
+	logger.InfoCtx(ctx, "answer purged", "answer_id", id)
+	return nil
+}
+
+func VoteAnswer(ctx context.Context, answerVoter AnswerVoter, id int, userID uuid.UUID, dto *answers.VoteDto) (*answers.Answer, error) {
+	ctx, span := tracing.Start(ctx, "answers.VoteAnswer")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	answer, err := answerVoter.AnswerVote(ctx, id, userID, dto.Value)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false},
+		)
+	}
+	return answer, nil
+}
@@ -6,10 +6,13 @@ import (
 	"testing"
 
 	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/authz"
 	"github.com/gengeo7/highlitent/storage"
 	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/users"
 	"github.com/gengeo7/highlitent/utils"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 )
 
 type mockAnswerGetter struct {
@@ -73,7 +76,7 @@ func TestGetAnswer(t *testing.T) {
 				}
 				var gotApiError *apierror.ApiError
 				if errors.As(gotErr, &gotApiError) {
-					if gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
 						t.Fatalf("GetAnswer(): %v, want: %v", gotErr, tt.wantErr)
 					}
 				} else {
@@ -98,7 +101,7 @@ type mockAnswerCreater struct {
 	ReturnedError error
 }
 
-func (m *mockAnswerCreater) AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int) (*answers.Answer, error) {
+func (m *mockAnswerCreater) AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int, userID uuid.UUID) (*answers.Answer, error) {
 	return m.ReturnedValue, m.ReturnedError
 }
 
@@ -126,22 +129,46 @@ func TestCreateAnswer(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "empty dto",
+			answerCreater: &mockAnswerCreater{
+				ReturnedValue: nil,
+				ReturnedError: nil,
+			},
+			dto:        nil,
+			questionID: 1,
+			want:       nil,
+			wantErr:    utils.EmptyDto(nil),
+		},
 		{
 			name: "question not found",
 			answerCreater: &mockAnswerCreater{
 				ReturnedValue: nil,
 				ReturnedError: storage.ErrDbNotFound,
 			},
+			dto:        &answers.AnswerDto{},
 			questionID: 1,
 			want:       nil,
 			wantErr:    utils.QuestionNotFound(nil),
 		},
+		{
+			name: "user not found",
+			answerCreater: &mockAnswerCreater{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbUserNotFound,
+			},
+			dto:        &answers.AnswerDto{},
+			questionID: 1,
+			want:       nil,
+			wantErr:    utils.UserNotFound(nil),
+		},
 		{
 			name: "deadline exceeded",
 			answerCreater: &mockAnswerCreater{
 				ReturnedValue: nil,
 				ReturnedError: context.DeadlineExceeded,
 			},
+			dto:        &answers.AnswerDto{},
 			questionID: 1,
 			want:       nil,
 			wantErr:    utils.DeadlineDbError(nil),
@@ -149,14 +176,14 @@ func TestCreateAnswer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := CreateAnswer(context.Background(), tt.answerCreater, tt.dto, tt.questionID)
+			got, gotErr := CreateAnswer(context.Background(), tt.answerCreater, tt.dto, tt.questionID, uuid.New())
 			if gotErr != nil {
 				if tt.wantErr == nil {
 					t.Fatalf("CreateAnswer() failed: %v", gotErr)
 				}
 				var gotApiError *apierror.ApiError
 				if errors.As(gotErr, &gotApiError) {
-					if gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
 						t.Fatalf("CreateAnswer(): %v, want: %v", gotErr, tt.wantErr)
 					}
 				} else {
@@ -176,8 +203,153 @@ func TestCreateAnswer(t *testing.T) {
 	}
 }
 
+type mockPolicy struct {
+	Allowed bool
+}
+
+func (m *mockPolicy) Can(actor authz.Actor, action authz.Action, authorID *uuid.UUID) bool {
+	return m.Allowed
+}
+
+type mockAnswerUpdater struct {
+	ReturnedValue *answers.Answer
+	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockAnswerUpdater) AnswerAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
+}
+
+func (m *mockAnswerUpdater) AnswerUpdate(ctx context.Context, id int, dto *answers.AnswerUpdateDto, editorID uuid.UUID) (*answers.Answer, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestUpdateAnswer(t *testing.T) {
+	tests := []struct {
+		name          string
+		answerUpdater AnswerUpdater
+		policy        authz.Policy
+		id            int
+		dto           *answers.AnswerUpdateDto
+		want          *answers.Answer
+		wantErr       *apierror.ApiError
+	}{
+		{
+			name: "updated",
+			answerUpdater: &mockAnswerUpdater{
+				ReturnedValue: &answers.Answer{
+					Text: "test",
+				},
+				ReturnedError: nil,
+			},
+			policy: &mockPolicy{Allowed: true},
+			id:     1,
+			dto:    &answers.AnswerUpdateDto{Text: "test"},
+			want: &answers.Answer{
+				Text: "test",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "empty dto",
+			answerUpdater: &mockAnswerUpdater{
+				ReturnedValue: nil,
+				ReturnedError: nil,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			id:      1,
+			dto:     nil,
+			want:    nil,
+			wantErr: utils.EmptyDto(nil),
+		},
+		{
+			name: "not found",
+			answerUpdater: &mockAnswerUpdater{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			id:      1,
+			dto:     &answers.AnswerUpdateDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "deadline exceeded",
+			answerUpdater: &mockAnswerUpdater{
+				ReturnedValue: nil,
+				ReturnedError: context.DeadlineExceeded,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			id:      1,
+			dto:     &answers.AnswerUpdateDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+		{
+			name: "author not found",
+			answerUpdater: &mockAnswerUpdater{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			id:      1,
+			dto:     &answers.AnswerUpdateDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			answerUpdater: &mockAnswerUpdater{
+				ReturnedValue: &answers.Answer{
+					Text: "test",
+				},
+			},
+			policy:  &mockPolicy{Allowed: false},
+			id:      1,
+			dto:     &answers.AnswerUpdateDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := UpdateAnswer(context.Background(), tt.answerUpdater, tt.policy, authz.Actor{UserID: uuid.New()}, tt.id, tt.dto)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("UpdateAnswer() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("UpdateAnswer(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("UpdateAnswer() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("UpdateAnswer() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(*tt.want, *got); diff != "" {
+				t.Errorf("UpdateAnswer() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
 type mockAnswerDeleter struct {
 	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockAnswerDeleter) AnswerAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
 }
 
 func (m *mockAnswerDeleter) AnswerDelete(ctx context.Context, id int) error {
@@ -188,6 +360,7 @@ func TestDeleteAnswer(t *testing.T) {
 	tests := []struct {
 		name          string
 		answerDeleter AnswerDeleter
+		policy        authz.Policy
 		id            int
 		wantErr       *apierror.ApiError
 	}{
@@ -196,6 +369,7 @@ func TestDeleteAnswer(t *testing.T) {
 			answerDeleter: &mockAnswerDeleter{
 				ReturnedError: nil,
 			},
+			policy:  &mockPolicy{Allowed: true},
 			id:      1,
 			wantErr: nil,
 		},
@@ -204,6 +378,7 @@ func TestDeleteAnswer(t *testing.T) {
 			answerDeleter: &mockAnswerDeleter{
 				ReturnedError: storage.ErrDbNotFound,
 			},
+			policy:  &mockPolicy{Allowed: true},
 			id:      1,
 			wantErr: utils.AnswerNotFound(nil),
 		},
@@ -212,20 +387,39 @@ func TestDeleteAnswer(t *testing.T) {
 			answerDeleter: &mockAnswerDeleter{
 				ReturnedError: context.DeadlineExceeded,
 			},
+			policy:  &mockPolicy{Allowed: true},
 			id:      1,
 			wantErr: utils.DeadlineDbError(nil),
 		},
+		{
+			name: "author not found",
+			answerDeleter: &mockAnswerDeleter{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			id:      1,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			answerDeleter: &mockAnswerDeleter{
+				ReturnedError: nil,
+			},
+			policy:  &mockPolicy{Allowed: false},
+			id:      1,
+			wantErr: utils.Forbidden(nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotErr := DeleteAnswer(context.Background(), tt.answerDeleter, tt.id)
+			gotErr := DeleteAnswer(context.Background(), tt.answerDeleter, tt.policy, authz.Actor{UserID: uuid.New()}, tt.id)
 			if gotErr != nil {
 				if tt.wantErr == nil {
 					t.Fatalf("DeleteAnswer() failed: %v", gotErr)
 				}
 				var gotApiError *apierror.ApiError
 				if errors.As(gotErr, &gotApiError) {
-					if gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
 						t.Fatalf("DeleteAnswer(): %v, want: %v", gotErr, tt.wantErr)
 					}
 				} else {
@@ -240,3 +434,499 @@ func TestDeleteAnswer(t *testing.T) {
 		})
 	}
 }
+
+type mockAnswerVoter struct {
+	ReturnedValue *answers.Answer
+	ReturnedError error
+}
+
+func (m *mockAnswerVoter) AnswerVote(ctx context.Context, id int, userID uuid.UUID, value int) (*answers.Answer, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestVoteAnswer(t *testing.T) {
+	tests := []struct {
+		name        string
+		answerVoter AnswerVoter
+		id          int
+		dto         *answers.VoteDto
+		want        *answers.Answer
+		wantErr     *apierror.ApiError
+	}{
+		{
+			name: "voted",
+			answerVoter: &mockAnswerVoter{
+				ReturnedValue: &answers.Answer{
+					Text:  "test",
+					Score: 1,
+				},
+				ReturnedError: nil,
+			},
+			id:  1,
+			dto: &answers.VoteDto{Value: 1},
+			want: &answers.Answer{
+				Text:  "test",
+				Score: 1,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "empty dto",
+			answerVoter: &mockAnswerVoter{
+				ReturnedValue: nil,
+				ReturnedError: nil,
+			},
+			id:      1,
+			dto:     nil,
+			want:    nil,
+			wantErr: utils.EmptyDto(nil),
+		},
+		{
+			name: "answer not found",
+			answerVoter: &mockAnswerVoter{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			id:      1,
+			dto:     &answers.VoteDto{Value: -1},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "user not found",
+			answerVoter: &mockAnswerVoter{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbUserNotFound,
+			},
+			id:      1,
+			dto:     &answers.VoteDto{Value: 1},
+			want:    nil,
+			wantErr: utils.UserNotFound(nil),
+		},
+		{
+			name: "deadline exceeded",
+			answerVoter: &mockAnswerVoter{
+				ReturnedValue: nil,
+				ReturnedError: context.DeadlineExceeded,
+			},
+			id:      1,
+			dto:     &answers.VoteDto{Value: 1},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := VoteAnswer(context.Background(), tt.answerVoter, tt.id, uuid.New(), tt.dto)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("VoteAnswer() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("VoteAnswer(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("VoteAnswer() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("VoteAnswer() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(*tt.want, *got); diff != "" {
+				t.Errorf("VoteAnswer() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockAnswerRevisionsGetter struct {
+	ReturnedValue []answers.Revision
+	ReturnedError error
+	Trashed       bool
+}
+
+func (m *mockAnswerRevisionsGetter) AnswerRevisionsGet(ctx context.Context, id int) ([]answers.Revision, error) {
+	if m.Trashed {
+		return nil, storage.ErrDbNotFound
+	}
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func (m *mockAnswerRevisionsGetter) AnswerRevisionsGetUnscoped(ctx context.Context, id int) ([]answers.Revision, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestGetAnswerRevisions(t *testing.T) {
+	tests := []struct {
+		name            string
+		revisionsGetter AnswerRevisionsGetter
+		actor           *authz.Actor
+		want            []answers.Revision
+		wantErr         *apierror.ApiError
+	}{
+		{
+			name: "ok",
+			revisionsGetter: &mockAnswerRevisionsGetter{
+				ReturnedValue: []answers.Revision{{ID: 2, Text: "second"}, {ID: 1, Text: "first"}},
+			},
+			actor:   nil,
+			want:    []answers.Revision{{ID: 2, Text: "second"}, {ID: 1, Text: "first"}},
+			wantErr: nil,
+		},
+		{
+			name: "in trash anonymous",
+			revisionsGetter: &mockAnswerRevisionsGetter{
+				ReturnedValue: []answers.Revision{{ID: 1, Text: "first"}},
+				Trashed:       true,
+			},
+			actor:   nil,
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "in trash user",
+			revisionsGetter: &mockAnswerRevisionsGetter{
+				ReturnedValue: []answers.Revision{{ID: 1, Text: "first"}},
+				Trashed:       true,
+			},
+			actor:   &authz.Actor{UserID: uuid.New(), Role: users.RoleUser},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "in trash moderator",
+			revisionsGetter: &mockAnswerRevisionsGetter{
+				ReturnedValue: []answers.Revision{{ID: 1, Text: "first"}},
+				Trashed:       true,
+			},
+			actor:   &authz.Actor{UserID: uuid.New(), Role: users.RoleModerator},
+			want:    []answers.Revision{{ID: 1, Text: "first"}},
+			wantErr: nil,
+		},
+		{
+			name: "not found",
+			revisionsGetter: &mockAnswerRevisionsGetter{
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "deadline exceeded",
+			revisionsGetter: &mockAnswerRevisionsGetter{
+				ReturnedError: context.DeadlineExceeded,
+			},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := GetAnswerRevisions(context.Background(), tt.revisionsGetter, authz.NewOwnerPolicy(), tt.actor, 1)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("GetAnswerRevisions() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("GetAnswerRevisions(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("GetAnswerRevisions() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("GetAnswerRevisions() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetAnswerRevisions() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockAnswerRollbacker struct {
+	mockAnswerUpdater
+	Revision      *answers.Revision
+	RevisionError error
+	GotDto        *answers.AnswerUpdateDto
+	GotEditorID   uuid.UUID
+}
+
+func (m *mockAnswerRollbacker) AnswerRevisionGet(ctx context.Context, id int, revisionID int) (*answers.Revision, error) {
+	return m.Revision, m.RevisionError
+}
+
+func (m *mockAnswerRollbacker) AnswerUpdate(ctx context.Context, id int, dto *answers.AnswerUpdateDto, editorID uuid.UUID) (*answers.Answer, error) {
+	m.GotDto = dto
+	m.GotEditorID = editorID
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestRollbackAnswer(t *testing.T) {
+	tests := []struct {
+		name       string
+		rollbacker *mockAnswerRollbacker
+		dto        *answers.RollbackDto
+		policy     authz.Policy
+		want       *answers.Answer
+		wantDto    *answers.AnswerUpdateDto
+		wantErr    *apierror.ApiError
+	}{
+		{
+			name: "rolled back",
+			rollbacker: &mockAnswerRollbacker{
+				mockAnswerUpdater: mockAnswerUpdater{
+					ReturnedValue: &answers.Answer{Text: "old"},
+				},
+				Revision: &answers.Revision{ID: 3, Text: "old"},
+			},
+			dto:     &answers.RollbackDto{Reason: "typo"},
+			policy:  &mockPolicy{Allowed: true},
+			want:    &answers.Answer{Text: "old"},
+			wantDto: &answers.AnswerUpdateDto{Text: "old", Reason: "typo"},
+			wantErr: nil,
+		},
+		{
+			name: "without reason",
+			rollbacker: &mockAnswerRollbacker{
+				mockAnswerUpdater: mockAnswerUpdater{
+					ReturnedValue: &answers.Answer{Text: "old"},
+				},
+				Revision: &answers.Revision{ID: 3, Text: "old"},
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    &answers.Answer{Text: "old"},
+			wantDto: &answers.AnswerUpdateDto{Text: "old"},
+			wantErr: nil,
+		},
+		{
+			name: "revision not found",
+			rollbacker: &mockAnswerRollbacker{
+				RevisionError: storage.ErrDbRevisionNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.RevisionNotFound(nil),
+		},
+		{
+			name: "answer not found",
+			rollbacker: &mockAnswerRollbacker{
+				mockAnswerUpdater: mockAnswerUpdater{
+					AuthorError: storage.ErrDbNotFound,
+				},
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			rollbacker: &mockAnswerRollbacker{
+				Revision: &answers.Revision{ID: 3, Text: "old"},
+			},
+			policy:  &mockPolicy{Allowed: false},
+			want:    nil,
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := authz.Actor{UserID: uuid.New()}
+			got, gotErr := RollbackAnswer(context.Background(), tt.rollbacker, tt.policy, actor, 1, 3, tt.dto)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("RollbackAnswer() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("RollbackAnswer(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("RollbackAnswer() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("RollbackAnswer() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("RollbackAnswer() mismatch:\n %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDto, tt.rollbacker.GotDto); diff != "" {
+				t.Errorf("RollbackAnswer() dto mismatch:\n %s", diff)
+			}
+			if tt.rollbacker.GotEditorID != actor.UserID {
+				t.Errorf("RollbackAnswer() editor: %v, want: %v", tt.rollbacker.GotEditorID, actor.UserID)
+			}
+		})
+	}
+}
+
+type mockAnswerRestorer struct {
+	ReturnedValue *answers.Answer
+	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockAnswerRestorer) AnswerAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
+}
+
+func (m *mockAnswerRestorer) AnswerRestore(ctx context.Context, id int) (*answers.Answer, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestRestoreAnswer(t *testing.T) {
+	tests := []struct {
+		name     string
+		restorer AnswerRestorer
+		policy   authz.Policy
+		want     *answers.Answer
+		wantErr  *apierror.ApiError
+	}{
+		{
+			name: "restored",
+			restorer: &mockAnswerRestorer{
+				ReturnedValue: &answers.Answer{Text: "test"},
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    &answers.Answer{Text: "test"},
+			wantErr: nil,
+		},
+		{
+			name: "not in trash",
+			restorer: &mockAnswerRestorer{
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.AnswerNotFoundInTrash(nil),
+		},
+		{
+			name: "answer not found",
+			restorer: &mockAnswerRestorer{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			restorer: &mockAnswerRestorer{
+				ReturnedValue: &answers.Answer{Text: "test"},
+			},
+			policy:  &mockPolicy{Allowed: false},
+			want:    nil,
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := RestoreAnswer(context.Background(), tt.restorer, tt.policy, authz.Actor{UserID: uuid.New()}, 1)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("RestoreAnswer() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("RestoreAnswer(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("RestoreAnswer() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("RestoreAnswer() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("RestoreAnswer() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockAnswerPurger struct {
+	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockAnswerPurger) AnswerAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
+}
+
+func (m *mockAnswerPurger) AnswerPurge(ctx context.Context, id int) error {
+	return m.ReturnedError
+}
+
+func TestPurgeAnswer(t *testing.T) {
+	tests := []struct {
+		name    string
+		purger  AnswerPurger
+		policy  authz.Policy
+		wantErr *apierror.ApiError
+	}{
+		{
+			name:    "purged",
+			purger:  &mockAnswerPurger{},
+			policy:  &mockPolicy{Allowed: true},
+			wantErr: nil,
+		},
+		{
+			name: "not found",
+			purger: &mockAnswerPurger{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name:    "forbidden",
+			purger:  &mockAnswerPurger{},
+			policy:  &mockPolicy{Allowed: false},
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotErr := PurgeAnswer(context.Background(), tt.purger, tt.policy, authz.Actor{UserID: uuid.New()}, 1)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("PurgeAnswer() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("PurgeAnswer(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("PurgeAnswer() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("PurgeAnswer() succeeded unexpectedly")
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package comments
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/uuid"
+)
+
+type CommentCreater interface {
+	CommentCreate(ctx context.Context, dto *comments.CommentDto, answerID int, userID uuid.UUID) (*comments.Comment, error)
+}
+
+type CommentsGetter interface {
+	CommentsGet(ctx context.Context, answerID int) ([]comments.Comment, error)
+}
+
+func CreateComment(ctx context.Context, commentCreater CommentCreater, dto *comments.CommentDto, answerID int, userID uuid.UUID) (*comments.Comment, error) {
+	ctx, span := tracing.Start(ctx, "comments.CreateComment")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	comment, err := commentCreater.CommentCreate(ctx, dto, answerID, userID)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrCommentNotFound, Creator: utils.CommentNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false},
+		)
+	}
+	return comment, nil
+}
+
+// GetComments returns the comments of an answer as threads, the
+// storage returns them flat from the oldest to the newest.
+func GetComments(ctx context.Context, commentsGetter CommentsGetter, answerID int) ([]*comments.CommentTree, error) {
+	ctx, span := tracing.Start(ctx, "comments.GetComments")
+	defer span.End()
+
+	cs, err := commentsGetter.CommentsGet(ctx, answerID)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.AnswerNotFound, CheckErr: false})
+	}
+	return buildTree(cs), nil
+}
+
+func buildTree(cs []comments.Comment) []*comments.CommentTree {
+	nodes := make(map[uint]*comments.CommentTree, len(cs))
+	for _, c := range cs {
+		nodes[c.ID] = &comments.CommentTree{Comment: c, Replies: []*comments.CommentTree{}}
+	}
+
+	roots := make([]*comments.CommentTree, 0)
+	for _, c := range cs {
+		node := nodes[c.ID]
+		if c.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent, have := nodes[*c.ParentID]
+		if !have {
+			// the parent is never missing with foreign keys,
+			// but a lost reply is still better shown at the top
+			roots = append(roots, node)
+			continue
+		}
+		parent.Replies = append(parent.Replies, node)
+	}
+	return roots
+}
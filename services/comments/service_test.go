@@ -0,0 +1,236 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+type mockCommentCreater struct {
+	ReturnedValue *comments.Comment
+	ReturnedError error
+}
+
+func (m *mockCommentCreater) CommentCreate(ctx context.Context, dto *comments.CommentDto, answerID int, userID uuid.UUID) (*comments.Comment, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestCreateComment(t *testing.T) {
+	tests := []struct {
+		name           string
+		commentCreater CommentCreater
+		dto            *comments.CommentDto
+		want           *comments.Comment
+		wantErr        *apierror.ApiError
+	}{
+		{
+			name: "created",
+			commentCreater: &mockCommentCreater{
+				ReturnedValue: &comments.Comment{
+					Text: "test",
+				},
+				ReturnedError: nil,
+			},
+			dto: &comments.CommentDto{Text: "test"},
+			want: &comments.Comment{
+				Text: "test",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "empty dto",
+			commentCreater: &mockCommentCreater{
+				ReturnedValue: nil,
+				ReturnedError: nil,
+			},
+			dto:     nil,
+			want:    nil,
+			wantErr: utils.EmptyDto(nil),
+		},
+		{
+			name: "answer not found",
+			commentCreater: &mockCommentCreater{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			dto:     &comments.CommentDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "parent not found",
+			commentCreater: &mockCommentCreater{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbCommentNotFound,
+			},
+			dto:     &comments.CommentDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.CommentNotFound(nil),
+		},
+		{
+			name: "user not found",
+			commentCreater: &mockCommentCreater{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbUserNotFound,
+			},
+			dto:     &comments.CommentDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.UserNotFound(nil),
+		},
+		{
+			name: "deadline exceeded",
+			commentCreater: &mockCommentCreater{
+				ReturnedValue: nil,
+				ReturnedError: context.DeadlineExceeded,
+			},
+			dto:     &comments.CommentDto{Text: "test"},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := CreateComment(context.Background(), tt.commentCreater, tt.dto, 1, uuid.New())
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("CreateComment() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("CreateComment(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("CreateComment() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("CreateComment() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("CreateComment() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockCommentsGetter struct {
+	ReturnedValue []comments.Comment
+	ReturnedError error
+}
+
+func (m *mockCommentsGetter) CommentsGet(ctx context.Context, answerID int) ([]comments.Comment, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func uintPtr(i uint) *uint {
+	return &i
+}
+
+func TestGetComments(t *testing.T) {
+	tests := []struct {
+		name           string
+		commentsGetter CommentsGetter
+		want           []*comments.CommentTree
+		wantErr        *apierror.ApiError
+	}{
+		{
+			name: "threads",
+			commentsGetter: &mockCommentsGetter{
+				ReturnedValue: []comments.Comment{
+					{ID: 1, Text: "root"},
+					{ID: 2, Text: "second root"},
+					{ID: 3, ParentID: uintPtr(1), Text: "reply"},
+					{ID: 4, ParentID: uintPtr(3), Text: "nested reply"},
+					{ID: 5, ParentID: uintPtr(1), Text: "another reply"},
+				},
+			},
+			want: []*comments.CommentTree{
+				{
+					Comment: comments.Comment{ID: 1, Text: "root"},
+					Replies: []*comments.CommentTree{
+						{
+							Comment: comments.Comment{ID: 3, ParentID: uintPtr(1), Text: "reply"},
+							Replies: []*comments.CommentTree{
+								{
+									Comment: comments.Comment{ID: 4, ParentID: uintPtr(3), Text: "nested reply"},
+									Replies: []*comments.CommentTree{},
+								},
+							},
+						},
+						{
+							Comment: comments.Comment{ID: 5, ParentID: uintPtr(1), Text: "another reply"},
+							Replies: []*comments.CommentTree{},
+						},
+					},
+				},
+				{
+					Comment: comments.Comment{ID: 2, Text: "second root"},
+					Replies: []*comments.CommentTree{},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "no comments",
+			commentsGetter: &mockCommentsGetter{
+				ReturnedValue: []comments.Comment{},
+			},
+			want:    []*comments.CommentTree{},
+			wantErr: nil,
+		},
+		{
+			name: "answer not found",
+			commentsGetter: &mockCommentsGetter{
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			want:    nil,
+			wantErr: utils.AnswerNotFound(nil),
+		},
+		{
+			name: "deadline exceeded",
+			commentsGetter: &mockCommentsGetter{
+				ReturnedError: context.DeadlineExceeded,
+			},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := GetComments(context.Background(), tt.commentsGetter, 1)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("GetComments() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("GetComments(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("GetComments() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("GetComments() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetComments() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gengeo7/highlitent/logger"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/health"
+)
+
+type Checker interface {
+	Ping(ctx context.Context) error
+	Migrations(ctx context.Context) (*health.Migrations, error)
+}
+
+// Live reports that the process serves requests, it checks nothing
+// else so a slow database doesn't get the process restarted.
+func Live() *health.Report {
+	return &health.Report{Status: health.StatusOk, Checks: map[string]health.Check{}}
+}
+
+// Ready checks that the storage is reachable and migrated. The endpoint
+// is public, so the errors are logged and the report only names what
+// failed.
+func Ready(ctx context.Context, checker Checker) *health.Report {
+	ctx, span := tracing.Start(ctx, "health.Ready")
+	defer span.End()
+
+	checks := map[string]health.Check{
+		"database": checkPing(ctx, checker),
+	}
+	if check, have := checkMigrations(ctx, checker); have {
+		checks["migrations"] = check
+	}
+
+	report := &health.Report{Status: health.StatusOk, Checks: checks}
+	for _, check := range checks {
+		if check.Status != health.StatusOk {
+			report.Status = health.StatusFail
+		}
+	}
+	return report
+}
+
+func checkPing(ctx context.Context, checker Checker) health.Check {
+	if err := checker.Ping(ctx); err != nil {
+		logger.ErrorCtx(ctx, "database ping failed", "error", err)
+		return health.Check{Status: health.StatusFail, Error: "database is unreachable"}
+	}
+	return health.Check{Status: health.StatusOk}
+}
+
+func checkMigrations(ctx context.Context, checker Checker) (health.Check, bool) {
+	migrations, err := checker.Migrations(ctx)
+	if err != nil {
+		logger.ErrorCtx(ctx, "migrations check failed", "error", err)
+		return health.Check{Status: health.StatusFail, Error: "migration version is unknown"}, true
+	}
+	if migrations == nil {
+		return health.Check{}, false
+	}
+	if migrations.Current < migrations.Latest {
+		return health.Check{
+			Status:  health.StatusFail,
+			Error:   fmt.Sprintf("database is at version %d, latest is %d", migrations.Current, migrations.Latest),
+			Details: migrations,
+		}, true
+	}
+	return health.Check{Status: health.StatusOk, Details: migrations}, true
+}
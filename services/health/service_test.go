@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gengeo7/highlitent/types/health"
+	"github.com/google/go-cmp/cmp"
+)
+
+type mockChecker struct {
+	PingError       error
+	MigrationsValue *health.Migrations
+	MigrationsError error
+}
+
+func (m *mockChecker) Ping(ctx context.Context) error {
+	return m.PingError
+}
+
+func (m *mockChecker) Migrations(ctx context.Context) (*health.Migrations, error) {
+	return m.MigrationsValue, m.MigrationsError
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker *mockChecker
+		want    *health.Report
+	}{
+		{
+			name: "ready",
+			checker: &mockChecker{
+				MigrationsValue: &health.Migrations{Current: 13, Latest: 13},
+			},
+			want: &health.Report{
+				Status: health.StatusOk,
+				Checks: map[string]health.Check{
+					"database":   {Status: health.StatusOk},
+					"migrations": {Status: health.StatusOk, Details: &health.Migrations{Current: 13, Latest: 13}},
+				},
+			},
+		},
+		{
+			name:    "storage without migrations",
+			checker: &mockChecker{},
+			want: &health.Report{
+				Status: health.StatusOk,
+				Checks: map[string]health.Check{
+					"database": {Status: health.StatusOk},
+				},
+			},
+		},
+		{
+			name: "database down",
+			checker: &mockChecker{
+				PingError:       errors.New("connection refused"),
+				MigrationsError: errors.New("connection refused"),
+			},
+			want: &health.Report{
+				Status: health.StatusFail,
+				Checks: map[string]health.Check{
+					"database":   {Status: health.StatusFail, Error: "database is unreachable"},
+					"migrations": {Status: health.StatusFail, Error: "migration version is unknown"},
+				},
+			},
+		},
+		{
+			name: "migrations behind",
+			checker: &mockChecker{
+				MigrationsValue: &health.Migrations{Current: 12, Latest: 13},
+			},
+			want: &health.Report{
+				Status: health.StatusFail,
+				Checks: map[string]health.Check{
+					"database": {Status: health.StatusOk},
+					"migrations": {
+						Status:  health.StatusFail,
+						Error:   "database is at version 12, latest is 13",
+						Details: &health.Migrations{Current: 12, Latest: 13},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Ready(context.Background(), tt.checker)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Ready() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/idempotency"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/uuid"
+)
+
+// LockTimeout is how long a key stays reserved by a request in
+// progress. It is longer than the timeouts of the requests, so a key
+// still locked after it belongs to a request that was lost, e.g. with
+// the process that ran it, and the next request with the key runs.
+const LockTimeout = 30 * time.Second
+
+type Starter interface {
+	IdempotencyStart(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error)
+}
+
+type Finisher interface {
+	IdempotencyFinish(ctx context.Context, userID uuid.UUID, key string, status int, contentType string, body []byte) error
+	IdempotencyDelete(ctx context.Context, userID uuid.UUID, key string) error
+}
+
+// Fingerprint identifies the request a key was first used with.
+func Fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Start reserves key of the user for the request with fingerprint for
+// ttl. It returns nil when the request has to run, or the record with
+// the response to replay when the request was already done.
+func Start(ctx context.Context, starter Starter, userID uuid.UUID, key string, fingerprint string, ttl time.Duration) (*idempotency.Record, error) {
+	ctx, span := tracing.Start(ctx, "idempotency.Start")
+	defer span.End()
+
+	if key == "" || len(key) > idempotency.MaxKeyLength {
+		return nil, utils.InvalidIdempotencyKey(nil)
+	}
+	now := time.Now()
+	record := &idempotency.Record{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+		LockedUntil: now.Add(LockTimeout),
+	}
+	existing, err := starter.IdempotencyStart(ctx, record)
+	if err == nil {
+		return nil, nil
+	}
+	if !storage.IsErrAlreadyExists(err) {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false})
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, utils.IdempotencyKeyReused(nil)
+	}
+	if existing.Status == 0 {
+		return nil, utils.IdempotencyKeyInProgress(nil)
+	}
+	return existing, nil
+}
+
+// Finish stores the response of the request started with key. Errors
+// are not stored, the key is released so the client can retry: a
+// problem is written in the language of the request and would be
+// replayed in it to a retry asking for another one.
+func Finish(ctx context.Context, finisher Finisher, userID uuid.UUID, key string, status int, contentType string, body []byte) error {
+	ctx, span := tracing.Start(ctx, "idempotency.Finish")
+	defer span.End()
+
+	var err error
+	if status >= http.StatusBadRequest {
+		err = finisher.IdempotencyDelete(ctx, userID, key)
+	} else {
+		err = finisher.IdempotencyFinish(ctx, userID, key, status, contentType, body)
+	}
+	if err != nil {
+		return utils.TestDbErr(err)
+	}
+	return nil
+}
+
+// Release drops the reservation of key when the request didn't finish.
+func Release(ctx context.Context, finisher Finisher, userID uuid.UUID, key string) error {
+	ctx, span := tracing.Start(ctx, "idempotency.Release")
+	defer span.End()
+
+	if err := finisher.IdempotencyDelete(ctx, userID, key); err != nil {
+		return utils.TestDbErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/idempotency"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+type mockStarter struct {
+	ReturnedValue *idempotency.Record
+	ReturnedError error
+	GotRecord     *idempotency.Record
+}
+
+func (m *mockStarter) IdempotencyStart(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error) {
+	m.GotRecord = record
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestStart(t *testing.T) {
+	done := &idempotency.Record{Key: "key", Fingerprint: "a", Status: 201, Body: []byte(`{"id":1}`)}
+	tests := []struct {
+		name    string
+		starter *mockStarter
+		key     string
+		want    *idempotency.Record
+		wantErr *apierror.ApiError
+	}{
+		{
+			name:    "new key",
+			starter: &mockStarter{},
+			key:     "key",
+			want:    nil,
+			wantErr: nil,
+		},
+		{
+			name:    "replay",
+			starter: &mockStarter{ReturnedValue: done, ReturnedError: storage.ErrDbAlreadyExists},
+			key:     "key",
+			want:    done,
+			wantErr: nil,
+		},
+		{
+			name: "other body",
+			starter: &mockStarter{
+				ReturnedValue: &idempotency.Record{Key: "key", Fingerprint: "b", Status: 201},
+				ReturnedError: storage.ErrDbAlreadyExists,
+			},
+			key:     "key",
+			want:    nil,
+			wantErr: utils.IdempotencyKeyReused(nil),
+		},
+		{
+			name: "in progress",
+			starter: &mockStarter{
+				ReturnedValue: &idempotency.Record{Key: "key", Fingerprint: "a"},
+				ReturnedError: storage.ErrDbAlreadyExists,
+			},
+			key:     "key",
+			want:    nil,
+			wantErr: utils.IdempotencyKeyInProgress(nil),
+		},
+		{
+			name:    "too long key",
+			starter: &mockStarter{},
+			key:     strings.Repeat("k", idempotency.MaxKeyLength+1),
+			want:    nil,
+			wantErr: utils.InvalidIdempotencyKey(nil),
+		},
+		{
+			name:    "deadline exceeded",
+			starter: &mockStarter{ReturnedError: context.DeadlineExceeded},
+			key:     "key",
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := Start(context.Background(), tt.starter, uuid.New(), tt.key, "a", time.Hour)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("Start() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("Start(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("Start() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("Start() succeeded unexpectedly")
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Start() mismatch (-want +got):\n%s", diff)
+			}
+			if tt.starter.GotRecord.ExpiresAt.Before(time.Now().Add(59 * time.Minute)) {
+				t.Errorf("Start() saved ExpiresAt %v, want about an hour from now", tt.starter.GotRecord.ExpiresAt)
+			}
+			if lock := time.Until(tt.starter.GotRecord.LockedUntil); lock <= 0 || lock > LockTimeout {
+				t.Errorf("Start() saved LockedUntil %v, want within LockTimeout from now", tt.starter.GotRecord.LockedUntil)
+			}
+		})
+	}
+}
+
+type mockFinisher struct {
+	Finished bool
+	Deleted  bool
+}
+
+func (m *mockFinisher) IdempotencyFinish(ctx context.Context, userID uuid.UUID, key string, status int, contentType string, body []byte) error {
+	m.Finished = true
+	return nil
+}
+
+func (m *mockFinisher) IdempotencyDelete(ctx context.Context, userID uuid.UUID, key string) error {
+	m.Deleted = true
+	return nil
+}
+
+func TestFinish(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   mockFinisher
+	}{
+		{name: "created", status: 201, want: mockFinisher{Finished: true}},
+		{name: "client error", status: 400, want: mockFinisher{Deleted: true}},
+		{name: "conflict", status: 409, want: mockFinisher{Deleted: true}},
+		{name: "server error", status: 500, want: mockFinisher{Deleted: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finisher := &mockFinisher{}
+			if err := Finish(context.Background(), finisher, uuid.New(), "key", tt.status, "application/json", nil); err != nil {
+				t.Fatalf("Finish() failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, *finisher); diff != "" {
+				t.Errorf("Finish() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint("POST", "/questions", []byte(`{"text":"a"}`))
+	if a != Fingerprint("POST", "/questions", []byte(`{"text":"a"}`)) {
+		t.Error("Fingerprint() differs for the same request")
+	}
+	if a == Fingerprint("POST", "/questions", []byte(`{"text":"b"}`)) {
+		t.Error("Fingerprint() same for another body")
+	}
+	if a == Fingerprint("POST", "/questions/1/answers", []byte(`{"text":"a"}`)) {
+		t.Error("Fingerprint() same for another path")
+	}
+}
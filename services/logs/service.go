@@ -0,0 +1,51 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/logger"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/logs"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+func levelDto(l slog.Level) *logs.LevelDto {
+	return &logs.LevelDto{Level: strings.ToLower(l.String())}
+}
+
+func GetLevel(ctx context.Context, policy authz.Policy, actor authz.Actor) (*logs.LevelDto, error) {
+	ctx, span := tracing.Start(ctx, "logs.GetLevel")
+	defer span.End()
+
+	if !policy.Can(actor, authz.ActionManageLogs, nil) {
+		return nil, utils.Forbidden(nil)
+	}
+	return levelDto(logger.Level()), nil
+}
+
+// SetLevel changes the log level of the running process, it is not
+// kept after a restart.
+func SetLevel(ctx context.Context, policy authz.Policy, actor authz.Actor, dto *logs.LevelDto) (*logs.LevelDto, error) {
+	ctx, span := tracing.Start(ctx, "logs.SetLevel")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	if !policy.Can(actor, authz.ActionManageLogs, nil) {
+		logger.WarnCtx(ctx, "action forbidden", "action", authz.ActionManageLogs)
+		return nil, utils.Forbidden(nil)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(dto.Level)); err != nil {
+		return nil, utils.InvalidLogLevel(nil)
+	}
+	previous := logger.Level()
+	logger.SetLevel(level)
+	// logged at warn so the change is seen at any level
+	logger.WarnCtx(ctx, "log level changed", "from", previous.String(), "to", level.String())
+	return levelDto(level), nil
+}
@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/logger"
+	"github.com/gengeo7/highlitent/types/logs"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+type mockPolicy struct {
+	Allowed bool
+}
+
+func (m *mockPolicy) Can(actor authz.Actor, action authz.Action, authorID *uuid.UUID) bool {
+	return m.Allowed
+}
+
+func TestSetLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    authz.Policy
+		dto       *logs.LevelDto
+		want      *logs.LevelDto
+		wantLevel slog.Level
+		wantErr   *apierror.ApiError
+	}{
+		{
+			name:      "ok",
+			policy:    &mockPolicy{Allowed: true},
+			dto:       &logs.LevelDto{Level: "debug"},
+			want:      &logs.LevelDto{Level: "debug"},
+			wantLevel: slog.LevelDebug,
+			wantErr:   nil,
+		},
+		{
+			name:      "forbidden",
+			policy:    &mockPolicy{Allowed: false},
+			dto:       &logs.LevelDto{Level: "debug"},
+			want:      nil,
+			wantLevel: slog.LevelInfo,
+			wantErr:   utils.Forbidden(nil),
+		},
+		{
+			name:      "unknown level",
+			policy:    &mockPolicy{Allowed: true},
+			dto:       &logs.LevelDto{Level: "verbose"},
+			want:      nil,
+			wantLevel: slog.LevelInfo,
+			wantErr:   utils.InvalidLogLevel(nil),
+		},
+		{
+			name:      "nil dto",
+			policy:    &mockPolicy{Allowed: true},
+			dto:       nil,
+			want:      nil,
+			wantLevel: slog.LevelInfo,
+			wantErr:   utils.EmptyDto(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger.SetLevel(slog.LevelInfo)
+			t.Cleanup(func() { logger.SetLevel(slog.LevelInfo) })
+
+			got, gotErr := SetLevel(context.Background(), tt.policy, authz.Actor{UserID: uuid.New()}, tt.dto)
+			if level := logger.Level(); level != tt.wantLevel {
+				t.Errorf("SetLevel() left level %v, want: %v", level, tt.wantLevel)
+			}
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("SetLevel() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("SetLevel(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("SetLevel() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("SetLevel() succeeded unexpectedly")
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SetLevel() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGetLevel(t *testing.T) {
+	logger.SetLevel(slog.LevelWarn)
+	t.Cleanup(func() { logger.SetLevel(slog.LevelInfo) })
+
+	got, err := GetLevel(context.Background(), &mockPolicy{Allowed: true}, authz.Actor{UserID: uuid.New()})
+	if err != nil {
+		t.Fatalf("GetLevel() failed: %v", err)
+	}
+	if diff := cmp.Diff(&logs.LevelDto{Level: "warn"}, got); diff != "" {
+		t.Errorf("GetLevel() mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = GetLevel(context.Background(), &mockPolicy{Allowed: false}, authz.Actor{UserID: uuid.New()})
+	var gotApiError *apierror.ApiError
+	if !errors.As(err, &gotApiError) || gotApiError.StatusCode != utils.Forbidden(nil).StatusCode {
+		t.Errorf("GetLevel() = %v, want: %v", err, utils.Forbidden(nil))
+	}
+}
@@ -2,59 +2,292 @@ package questions
 
 import (
 	"context"
+	"slices"
 
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/logger"
 	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/common"
 	"github.com/gengeo7/highlitent/types/questions"
 	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/uuid"
 )
 
 type QuestionsGetter interface {
-	QuestionsGet(ctx context.Context) ([]questions.Question, error)
+	QuestionsGet(ctx context.Context, query *questions.QuestionsQuery) (*questions.QuestionsPage, error)
 }
 
 type QuestionCreater interface {
-	QuestionCreate(ctx context.Context, dto *questions.QuestionDto) (*questions.Question, error)
+	QuestionCreate(ctx context.Context, dto *questions.QuestionDto, userID uuid.UUID) (*questions.Question, error)
 }
 
 type QuestionGetter interface {
-	QuestionGet(ctx context.Context, id int) (*questions.QuestionWithAnswers, error)
+	QuestionGet(ctx context.Context, id int, query *answers.AnswersQuery) (*questions.QuestionWithAnswers, error)
+}
+
+type QuestionAuthorGetter interface {
+	QuestionAuthor(ctx context.Context, id int) (*uuid.UUID, error)
+}
+
+// QuestionTrashAuthorGetter finds the author of questions in the trash as well.
+type QuestionTrashAuthorGetter interface {
+	QuestionAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error)
+}
+
+type QuestionUpdater interface {
+	QuestionAuthorGetter
+	QuestionUpdate(ctx context.Context, id int, dto *questions.QuestionUpdateDto, editorID uuid.UUID) (*questions.Question, error)
+}
+
+type QuestionRevisionsGetter interface {
+	QuestionRevisionsGet(ctx context.Context, id int) ([]questions.Revision, error)
+	QuestionRevisionsGetUnscoped(ctx context.Context, id int) ([]questions.Revision, error)
+}
+
+type QuestionRollbacker interface {
+	QuestionUpdater
+	QuestionRevisionGet(ctx context.Context, id int, revisionID int) (*questions.Revision, error)
 }
 
 type QuestionDeleter interface {
+	QuestionAuthorGetter
 	QuestionDelete(ctx context.Context, id int) error
 }
 
-func GetAllQuestions(ctx context.Context, questionsGetter QuestionsGetter) ([]questions.Question, error) {
-	questions, err := questionsGetter.QuestionsGet(ctx)
+type QuestionRestorer interface {
+	QuestionTrashAuthorGetter
+	QuestionRestore(ctx context.Context, id int) (*questions.Question, error)
+}
+
+type QuestionPurger interface {
+	QuestionTrashAuthorGetter
+	QuestionPurge(ctx context.Context, id int) error
+}
+
+type QuestionAnswerAccepter interface {
+	QuestionAuthorGetter
+	QuestionAcceptAnswer(ctx context.Context, id int, answerID *int) (*questions.Question, error)
+}
+
+func GetAllQuestions(ctx context.Context, questionsGetter QuestionsGetter, query *questions.QuestionsQuery) (*questions.QuestionsPage, error) {
+	ctx, span := tracing.Start(ctx, "questions.GetAllQuestions")
+	defer span.End()
+
+	q := questions.QuestionsQuery{}
+	if query != nil {
+		q = *query
+	}
+	if q.Limit <= 0 || q.Limit > questions.MaxLimit {
+		q.Limit = questions.DefaultLimit
+	}
+	if q.Sort != common.OrderAsc {
+		q.Sort = common.OrderDesc
+	}
+	if len(q.Tags) > 0 {
+		q.Tags = slices.Compact(slices.Sorted(slices.Values(q.Tags)))
+	}
+
+	page, err := questionsGetter.QuestionsGet(ctx, &q)
 	if err != nil {
 		return nil, utils.TestDbErr(err)
 	}
-	return questions, nil
+	return page, nil
 }
 
-func CreateQuestion(ctx context.Context, questionCreater QuestionCreater, dto *questions.QuestionDto) (*questions.Question, error) {
+func CreateQuestion(ctx context.Context, questionCreater QuestionCreater, dto *questions.QuestionDto, userID uuid.UUID) (*questions.Question, error) {
+	ctx, span := tracing.Start(ctx, "questions.CreateQuestion")
+	defer span.End()
+
 	if dto == nil {
 		return nil, utils.EmptyDto(nil)
 	}
-	question, err := questionCreater.QuestionCreate(ctx, dto)
+	question, err := questionCreater.QuestionCreate(ctx, dto, userID)
 	if err != nil {
-		return nil, utils.TestDbErr(err)
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false})
 	}
 	return question, nil
 }
 
-func GetQuestionWithAnswers(ctx context.Context, questionGetter QuestionGetter, id int) (*questions.QuestionWithAnswers, error) {
-	questionWithAnswer, err := questionGetter.QuestionGet(ctx, id)
+func GetQuestionWithAnswers(ctx context.Context, questionGetter QuestionGetter, id int, query *answers.AnswersQuery) (*questions.QuestionWithAnswers, error) {
+	ctx, span := tracing.Start(ctx, "questions.GetQuestionWithAnswers")
+	defer span.End()
+
+	q := answers.AnswersQuery{}
+	if query != nil {
+		q = *query
+	}
+	if q.Limit <= 0 || q.Limit > answers.MaxLimit {
+		q.Limit = answers.DefaultLimit
+	}
+	switch q.Order {
+	case common.OrderDesc:
+	case answers.OrderScore:
+		if q.Cursor != nil && q.Cursor.Score == nil {
+			return nil, utils.InvalidCursor(nil)
+		}
+	default:
+		q.Order = common.OrderAsc
+	}
+
+	questionWithAnswer, err := questionGetter.QuestionGet(ctx, id, &q)
 	if err != nil {
 		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false})
 	}
 	return questionWithAnswer, nil
 }
 
-func DeleteQuestion(ctx context.Context, questionDeleter QuestionDeleter, id int) error {
+// authorizeQuestion checks that actor can do action on the question whose
+// author is returned by author.
+func authorizeQuestion(ctx context.Context, author func(ctx context.Context, id int) (*uuid.UUID, error), policy authz.Policy, actor authz.Actor, action authz.Action, id int) error {
+	authorID, err := author(ctx, id)
+	if err != nil {
+		return utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false})
+	}
+	if !policy.Can(actor, action, authorID) {
+		logger.WarnCtx(ctx, "action forbidden", "action", action, "question_id", id)
+		return utils.Forbidden(nil)
+	}
+	return nil
+}
+
+func UpdateQuestion(ctx context.Context, questionUpdater QuestionUpdater, policy authz.Policy, actor authz.Actor, id int, dto *questions.QuestionUpdateDto) (*questions.Question, error) {
+	ctx, span := tracing.Start(ctx, "questions.UpdateQuestion")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	if err := authorizeQuestion(ctx, questionUpdater.QuestionAuthor, policy, actor, authz.ActionEdit, id); err != nil {
+		return nil, err
+	}
+	question, err := questionUpdater.QuestionUpdate(ctx, id, dto, actor.UserID)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false},
+		)
+	}
+	return question, nil
+}
+
+// GetQuestionRevisions returns the history of the question. The history of
+// a question in the trash is shown only to actors who can view the
+// trash, actor is nil for anonymous requests.
+func GetQuestionRevisions(ctx context.Context, revisionsGetter QuestionRevisionsGetter, policy authz.Policy, actor *authz.Actor, id int) ([]questions.Revision, error) {
+	ctx, span := tracing.Start(ctx, "questions.GetQuestionRevisions")
+	defer span.End()
+
+	get := revisionsGetter.QuestionRevisionsGet
+	if actor != nil && policy.Can(*actor, authz.ActionViewTrash, nil) {
+		get = revisionsGetter.QuestionRevisionsGetUnscoped
+	}
+	revisions, err := get(ctx, id)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false})
+	}
+	return revisions, nil
+}
+
+// RollbackQuestion brings back the text of the revision as a new edit,
+// so the text it replaces is kept in the history as well. rollback is
+// nil when the request has no body.
+func RollbackQuestion(ctx context.Context, rollbacker QuestionRollbacker, policy authz.Policy, actor authz.Actor, id int, revisionID int, rollback *questions.RollbackDto) (*questions.Question, error) {
+	ctx, span := tracing.Start(ctx, "questions.RollbackQuestion")
+	defer span.End()
+
+	if err := authorizeQuestion(ctx, rollbacker.QuestionAuthor, policy, actor, authz.ActionEdit, id); err != nil {
+		return nil, err
+	}
+	revision, err := rollbacker.QuestionRevisionGet(ctx, id, revisionID)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrRevisionNotFound, Creator: utils.RevisionNotFound, CheckErr: false})
+	}
+	dto := questions.QuestionUpdateDto{Text: revision.Text}
+	if rollback != nil {
+		dto.Reason = rollback.Reason
+	}
+	question, err := rollbacker.QuestionUpdate(ctx, id, &dto, actor.UserID)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrUserNotFound, Creator: utils.UserNotFound, CheckErr: false},
+		)
+	}
+	logger.InfoCtx(ctx, "question rolled back", "question_id", id, "revision_id", revisionID)
+	return question, nil
+}
+
+func DeleteQuestion(ctx context.Context, questionDeleter QuestionDeleter, policy authz.Policy, actor authz.Actor, id int) error {
+	ctx, span := tracing.Start(ctx, "questions.DeleteQuestion")
+	defer span.End()
+
+	if err := authorizeQuestion(ctx, questionDeleter.QuestionAuthor, policy, actor, authz.ActionDelete, id); err != nil {
+		return err
+	}
 	err := questionDeleter.QuestionDelete(ctx, id)
 	if err != nil {
 		return utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false})
 	}
+	logger.InfoCtx(ctx, "question moved to trash", "question_id", id)
+	return nil
+}
+
+func RestoreQuestion(ctx context.Context, questionRestorer QuestionRestorer, policy authz.Policy, actor authz.Actor, id int) (*questions.Question, error) {
+	ctx, span := tracing.Start(ctx, "questions.RestoreQuestion")
+	defer span.End()
+
+	if err := authorizeQuestion(ctx, questionRestorer.QuestionAuthorUnscoped, policy, actor, authz.ActionRestore, id); err != nil {
+		return nil, err
+	}
+	question, err := questionRestorer.QuestionRestore(ctx, id)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFoundInTrash, CheckErr: false})
+	}
+	logger.InfoCtx(ctx, "question restored", "question_id", id)
+	return question, nil
+}
+
+func PurgeQuestion(ctx context.Context, questionPurger QuestionPurger, policy authz.Policy, actor authz.Actor, id int) error {
+	ctx, span := tracing.Start(ctx, "questions.PurgeQuestion")
+	defer span.End()
+
+	if err := authorizeQuestion(ctx, questionPurger.QuestionAuthorUnscoped, policy, actor, authz.ActionPurge, id); err != nil {
+		return err
+	}
+	err := questionPurger.QuestionPurge(ctx, id)
+	if err != nil {
+		return utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false})
+	}
+	logger.InfoCtx(ctx, "question purged", "question_id", id)
 	return nil
 }
+
+func AcceptAnswer(ctx context.Context, accepter QuestionAnswerAccepter, policy authz.Policy, actor authz.Actor, id int, answerID int) (*questions.Question, error) {
+	ctx, span := tracing.Start(ctx, "questions.AcceptAnswer")
+	defer span.End()
+
+	return acceptAnswer(ctx, accepter, policy, actor, id, &answerID)
+}
+
+func UnacceptAnswer(ctx context.Context, accepter QuestionAnswerAccepter, policy authz.Policy, actor authz.Actor, id int) (*questions.Question, error) {
+	ctx, span := tracing.Start(ctx, "questions.UnacceptAnswer")
+	defer span.End()
+
+	return acceptAnswer(ctx, accepter, policy, actor, id, nil)
+}
+
+func acceptAnswer(ctx context.Context, accepter QuestionAnswerAccepter, policy authz.Policy, actor authz.Actor, id int, answerID *int) (*questions.Question, error) {
+	if err := authorizeQuestion(ctx, accepter.QuestionAuthor, policy, actor, authz.ActionAccept, id); err != nil {
+		return nil, err
+	}
+	question, err := accepter.QuestionAcceptAnswer(ctx, id, answerID)
+	if err != nil {
+		return nil, utils.TestDbErr(err,
+			&utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.QuestionNotFound, CheckErr: false},
+			&utils.ErrDbCase{Func: storage.IsErrAnswerNotFound, Creator: utils.AnswerNotFound, CheckErr: false},
+		)
+	}
+	return question, nil
+}
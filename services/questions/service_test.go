@@ -6,66 +6,131 @@ import (
 	"testing"
 
 	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/authz"
 	"github.com/gengeo7/highlitent/storage"
 	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/common"
 	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/users"
 	"github.com/gengeo7/highlitent/utils"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 )
 
 type mockQuestionsGetter struct {
-	ReturnedValue []questions.Question
+	ReturnedValue *questions.QuestionsPage
 	ReturnedError error
+	GotQuery      *questions.QuestionsQuery
 }
 
-func (m *mockQuestionsGetter) QuestionsGet(ctx context.Context) ([]questions.Question, error) {
+func (m *mockQuestionsGetter) QuestionsGet(ctx context.Context, query *questions.QuestionsQuery) (*questions.QuestionsPage, error) {
+	m.GotQuery = query
 	return m.ReturnedValue, m.ReturnedError
 }
 
 func TestGetAllQuestions(t *testing.T) {
 	tests := []struct {
 		name            string
-		questionsGetter QuestionsGetter
-		want            []questions.Question
+		questionsGetter *mockQuestionsGetter
+		query           *questions.QuestionsQuery
+		wantQuery       *questions.QuestionsQuery
+		want            *questions.QuestionsPage
 		wantErr         *apierror.ApiError
 	}{
 		{
 			name: "ok",
 			questionsGetter: &mockQuestionsGetter{
-				ReturnedValue: []questions.Question{
+				ReturnedValue: &questions.QuestionsPage{
+					Questions: []questions.Question{
+						{
+							Text: "test1",
+						},
+						{
+							Text: "test2",
+						},
+					},
+					NextCursor: "next",
+				},
+				ReturnedError: nil,
+			},
+			query: &questions.QuestionsQuery{
+				Limit: 2,
+				Sort:  common.OrderAsc,
+			},
+			wantQuery: &questions.QuestionsQuery{
+				Limit: 2,
+				Sort:  common.OrderAsc,
+			},
+			want: &questions.QuestionsPage{
+				Questions: []questions.Question{
 					{
 						Text: "test1",
 					},
 					{
 						Text: "test2",
 					},
-					{
-						Text: "test3",
-					},
 				},
-				ReturnedError: nil,
+				NextCursor: "next",
 			},
-			want: []questions.Question{
-				{
-					Text: "test1",
-				},
-				{
-					Text: "test2",
+			wantErr: nil,
+		},
+		{
+			name: "duplicate tags",
+			questionsGetter: &mockQuestionsGetter{
+				ReturnedValue: &questions.QuestionsPage{
+					Questions: []questions.Question{},
 				},
-				{
-
-					Text: "test3",
+			},
+			query: &questions.QuestionsQuery{
+				Tags: []string{"sql", "go", "sql"},
+			},
+			wantQuery: &questions.QuestionsQuery{
+				Limit: questions.DefaultLimit,
+				Sort:  common.OrderDesc,
+				Tags:  []string{"go", "sql"},
+			},
+			want: &questions.QuestionsPage{
+				Questions: []questions.Question{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "empty list with default query",
+			questionsGetter: &mockQuestionsGetter{
+				ReturnedValue: &questions.QuestionsPage{
+					Questions: []questions.Question{},
 				},
+				ReturnedError: nil,
+			},
+			query: nil,
+			wantQuery: &questions.QuestionsQuery{
+				Limit: questions.DefaultLimit,
+				Sort:  common.OrderDesc,
+			},
+			want: &questions.QuestionsPage{
+				Questions: []questions.Question{},
 			},
 			wantErr: nil,
 		},
 		{
-			name: "empty list",
+			name: "limit over max",
 			questionsGetter: &mockQuestionsGetter{
-				ReturnedValue: []questions.Question{},
+				ReturnedValue: &questions.QuestionsPage{
+					Questions: []questions.Question{},
+				},
 				ReturnedError: nil,
 			},
-			want:    []questions.Question{},
+			query: &questions.QuestionsQuery{
+				Limit: questions.MaxLimit + 1,
+				Sort:  common.OrderDesc,
+			},
+			wantQuery: &questions.QuestionsQuery{
+				Limit: questions.DefaultLimit,
+				Sort:  common.OrderDesc,
+			},
+			want: &questions.QuestionsPage{
+				Questions: []questions.Question{},
+			},
 			wantErr: nil,
 		},
 		{
@@ -89,14 +154,14 @@ func TestGetAllQuestions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := GetAllQuestions(context.Background(), tt.questionsGetter)
+			got, gotErr := GetAllQuestions(context.Background(), tt.questionsGetter, tt.query)
 			if gotErr != nil {
 				if tt.wantErr == nil {
 					t.Fatalf("GetAllQuestions() failed: %v", gotErr)
 				}
 				var gotApiError *apierror.ApiError
 				if errors.As(gotErr, &gotApiError) {
-					if gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
 						t.Fatalf("GetAllQuestions(): %v, want: %v", gotErr, tt.wantErr)
 					}
 				} else {
@@ -109,6 +174,10 @@ func TestGetAllQuestions(t *testing.T) {
 				t.Fatal("GetAllQuestions() succeeded unexpectedly")
 			}
 
+			if diff := cmp.Diff(tt.wantQuery, tt.questionsGetter.GotQuery); diff != "" {
+				t.Errorf("GetAllQuestions() query mismatch:\n %s", diff)
+			}
+
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("GetAllQuestions() mismatch:\n %s", diff)
 			}
@@ -121,7 +190,7 @@ type mockQuestionCreater struct {
 	ReturnedError error
 }
 
-func (m *mockQuestionCreater) QuestionCreate(ctx context.Context, dto *questions.QuestionDto) (*questions.Question, error) {
+func (m *mockQuestionCreater) QuestionCreate(ctx context.Context, dto *questions.QuestionDto, userID uuid.UUID) (*questions.Question, error) {
 	return m.ReturnedValue, m.ReturnedError
 }
 
@@ -149,6 +218,18 @@ func TestCreateQuestion(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "user not found",
+			questionCreater: &mockQuestionCreater{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbUserNotFound,
+			},
+			dto: &questions.QuestionDto{
+				Text: "test",
+			},
+			want:    nil,
+			wantErr: utils.UserNotFound(nil),
+		},
 		{
 			name: "deadline exceeded",
 			questionCreater: &mockQuestionCreater{
@@ -164,14 +245,14 @@ func TestCreateQuestion(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := CreateQuestion(context.Background(), tt.questionCreater, tt.dto)
+			got, gotErr := CreateQuestion(context.Background(), tt.questionCreater, tt.dto, uuid.New())
 			if gotErr != nil {
 				if tt.wantErr == nil {
 					t.Fatalf("CreateQuestion() failed: %v", gotErr)
 				}
 				var gotApiError *apierror.ApiError
 				if errors.As(gotErr, &gotApiError) {
-					if gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
 						t.Fatalf("CreateQuestion(): %v, want: %v", gotErr, tt.wantErr)
 					}
 				} else {
@@ -194,17 +275,21 @@ func TestCreateQuestion(t *testing.T) {
 type mockQuestionGetter struct {
 	ReturnedValue *questions.QuestionWithAnswers
 	ReturnedError error
+	GotQuery      *answers.AnswersQuery
 }
 
-func (m *mockQuestionGetter) QuestionGet(ctx context.Context, id int) (*questions.QuestionWithAnswers, error) {
+func (m *mockQuestionGetter) QuestionGet(ctx context.Context, id int, query *answers.AnswersQuery) (*questions.QuestionWithAnswers, error) {
+	m.GotQuery = query
 	return m.ReturnedValue, m.ReturnedError
 }
 
 func TestGetQuestionWithAnswers(t *testing.T) {
 	tests := []struct {
 		name           string
-		questionGetter QuestionGetter
+		questionGetter *mockQuestionGetter
 		id             int
+		query          *answers.AnswersQuery
+		wantQuery      *answers.AnswersQuery
 		want           *questions.QuestionWithAnswers
 		wantErr        *apierror.ApiError
 	}{
@@ -220,9 +305,18 @@ func TestGetQuestionWithAnswers(t *testing.T) {
 							Text: "test1",
 						},
 					},
+					AnswersTotal: 1,
 				},
 			},
 			id: 1,
+			query: &answers.AnswersQuery{
+				Limit: 5,
+				Order: common.OrderDesc,
+			},
+			wantQuery: &answers.AnswersQuery{
+				Limit: 5,
+				Order: common.OrderDesc,
+			},
 			want: &questions.QuestionWithAnswers{
 				Question: questions.Question{
 					Text: "test",
@@ -232,9 +326,65 @@ func TestGetQuestionWithAnswers(t *testing.T) {
 						Text: "test1",
 					},
 				},
+				AnswersTotal: 1,
 			},
 			wantErr: nil,
 		},
+		{
+			name: "default query",
+			questionGetter: &mockQuestionGetter{
+				ReturnedValue: &questions.QuestionWithAnswers{
+					Question: questions.Question{
+						Text: "test",
+					},
+					Answers: []answers.Answer{},
+				},
+			},
+			id:    1,
+			query: nil,
+			wantQuery: &answers.AnswersQuery{
+				Limit: answers.DefaultLimit,
+				Order: common.OrderAsc,
+			},
+			want: &questions.QuestionWithAnswers{
+				Question: questions.Question{
+					Text: "test",
+				},
+				Answers: []answers.Answer{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "score order",
+			questionGetter: &mockQuestionGetter{
+				ReturnedValue: &questions.QuestionWithAnswers{
+					Answers: []answers.Answer{},
+				},
+			},
+			id: 1,
+			query: &answers.AnswersQuery{
+				Order: answers.OrderScore,
+			},
+			wantQuery: &answers.AnswersQuery{
+				Limit: answers.DefaultLimit,
+				Order: answers.OrderScore,
+			},
+			want: &questions.QuestionWithAnswers{
+				Answers: []answers.Answer{},
+			},
+			wantErr: nil,
+		},
+		{
+			name:           "score order with time cursor",
+			questionGetter: &mockQuestionGetter{},
+			id:             1,
+			query: &answers.AnswersQuery{
+				Cursor: &common.Cursor{ID: 1},
+				Order:  answers.OrderScore,
+			},
+			want:    nil,
+			wantErr: utils.InvalidCursor(nil),
+		},
 		{
 			name: "not found",
 			questionGetter: &mockQuestionGetter{
@@ -258,14 +408,14 @@ func TestGetQuestionWithAnswers(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := GetQuestionWithAnswers(context.Background(), tt.questionGetter, tt.id)
+			got, gotErr := GetQuestionWithAnswers(context.Background(), tt.questionGetter, tt.id, tt.query)
 			if gotErr != nil {
 				if tt.wantErr == nil {
 					t.Fatalf("CreateQuestion() failed: %v", gotErr)
 				}
 				var gotApiError *apierror.ApiError
 				if errors.As(gotErr, &gotApiError) {
-					if gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
 						t.Fatalf("CreateQuestion(): %v, want: %v", gotErr, tt.wantErr)
 					}
 				} else {
@@ -278,6 +428,10 @@ func TestGetQuestionWithAnswers(t *testing.T) {
 				t.Fatal("CreateQuestion() succeeded unexpectedly")
 			}
 
+			if diff := cmp.Diff(tt.wantQuery, tt.questionGetter.GotQuery); diff != "" {
+				t.Errorf("GetQuestionWithAnswers() query mismatch:\n %s", diff)
+			}
+
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("CreateQuestion() mismatch:\n %s", diff)
 			}
@@ -285,8 +439,163 @@ func TestGetQuestionWithAnswers(t *testing.T) {
 	}
 }
 
+type mockPolicy struct {
+	Allowed bool
+}
+
+func (m *mockPolicy) Can(actor authz.Actor, action authz.Action, authorID *uuid.UUID) bool {
+	return m.Allowed
+}
+
+type mockQuestionUpdater struct {
+	ReturnedValue *questions.Question
+	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockQuestionUpdater) QuestionAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
+}
+
+func (m *mockQuestionUpdater) QuestionUpdate(ctx context.Context, id int, dto *questions.QuestionUpdateDto, editorID uuid.UUID) (*questions.Question, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestUpdateQuestion(t *testing.T) {
+	tests := []struct {
+		name            string
+		questionUpdater QuestionUpdater
+		policy          authz.Policy
+		id              int
+		dto             *questions.QuestionUpdateDto
+		want            *questions.Question
+		wantErr         *apierror.ApiError
+	}{
+		{
+			name: "updated",
+			questionUpdater: &mockQuestionUpdater{
+				ReturnedValue: &questions.Question{
+					Text: "test",
+				},
+				ReturnedError: nil,
+			},
+			policy: &mockPolicy{Allowed: true},
+			id:     1,
+			dto: &questions.QuestionUpdateDto{
+				Text: "test",
+			},
+			want: &questions.Question{
+				Text: "test",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "empty dto",
+			questionUpdater: &mockQuestionUpdater{
+				ReturnedValue: nil,
+				ReturnedError: nil,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			id:      1,
+			dto:     nil,
+			want:    nil,
+			wantErr: utils.EmptyDto(nil),
+		},
+		{
+			name: "not found",
+			questionUpdater: &mockQuestionUpdater{
+				ReturnedValue: nil,
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			policy: &mockPolicy{Allowed: true},
+			id:     1,
+			dto: &questions.QuestionUpdateDto{
+				Text: "test",
+			},
+			want:    nil,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "deadline exceeded",
+			questionUpdater: &mockQuestionUpdater{
+				ReturnedValue: nil,
+				ReturnedError: context.DeadlineExceeded,
+			},
+			policy: &mockPolicy{Allowed: true},
+			id:     1,
+			dto: &questions.QuestionUpdateDto{
+				Text: "test",
+			},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+		{
+			name: "author not found",
+			questionUpdater: &mockQuestionUpdater{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy: &mockPolicy{Allowed: true},
+			id:     1,
+			dto: &questions.QuestionUpdateDto{
+				Text: "test",
+			},
+			want:    nil,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			questionUpdater: &mockQuestionUpdater{
+				ReturnedValue: &questions.Question{
+					Text: "test",
+				},
+			},
+			policy: &mockPolicy{Allowed: false},
+			id:     1,
+			dto: &questions.QuestionUpdateDto{
+				Text: "test",
+			},
+			want:    nil,
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := UpdateQuestion(context.Background(), tt.questionUpdater, tt.policy, authz.Actor{UserID: uuid.New()}, tt.id, tt.dto)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("UpdateQuestion() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("UpdateQuestion(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("UpdateQuestion() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("UpdateQuestion() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UpdateQuestion() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
 type mockQuestionDeleter struct {
 	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockQuestionDeleter) QuestionAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
 }
 
 func (m *mockQuestionDeleter) QuestionDelete(ctx context.Context, id int) error {
@@ -297,6 +606,7 @@ func TestDeleteQuestion(t *testing.T) {
 	tests := []struct {
 		name            string
 		questionDeleter QuestionDeleter
+		policy          authz.Policy
 		id              int
 		wantErr         *apierror.ApiError
 	}{
@@ -305,6 +615,7 @@ func TestDeleteQuestion(t *testing.T) {
 			questionDeleter: &mockQuestionDeleter{
 				ReturnedError: nil,
 			},
+			policy:  &mockPolicy{Allowed: true},
 			id:      1,
 			wantErr: nil,
 		},
@@ -313,6 +624,7 @@ func TestDeleteQuestion(t *testing.T) {
 			questionDeleter: &mockQuestionDeleter{
 				ReturnedError: storage.ErrDbNotFound,
 			},
+			policy:  &mockPolicy{Allowed: true},
 			id:      1,
 			wantErr: utils.QuestionNotFound(nil),
 		},
@@ -321,20 +633,39 @@ func TestDeleteQuestion(t *testing.T) {
 			questionDeleter: &mockQuestionDeleter{
 				ReturnedError: context.DeadlineExceeded,
 			},
+			policy:  &mockPolicy{Allowed: true},
 			id:      1,
 			wantErr: utils.DeadlineDbError(nil),
 		},
+		{
+			name: "author not found",
+			questionDeleter: &mockQuestionDeleter{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			id:      1,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			questionDeleter: &mockQuestionDeleter{
+				ReturnedError: nil,
+			},
+			policy:  &mockPolicy{Allowed: false},
+			id:      1,
+			wantErr: utils.Forbidden(nil),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotErr := DeleteQuestion(context.Background(), tt.questionDeleter, tt.id)
+			gotErr := DeleteQuestion(context.Background(), tt.questionDeleter, tt.policy, authz.Actor{UserID: uuid.New()}, tt.id)
 			if gotErr != nil {
 				if tt.wantErr == nil {
 					t.Fatalf("CreateQuestion() failed: %v", gotErr)
 				}
 				var gotApiError *apierror.ApiError
 				if errors.As(gotErr, &gotApiError) {
-					if gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
 						t.Fatalf("CreateQuestion(): %v, want: %v", gotErr, tt.wantErr)
 					}
 				} else {
@@ -349,3 +680,520 @@ func TestDeleteQuestion(t *testing.T) {
 		})
 	}
 }
+
+type mockQuestionAnswerAccepter struct {
+	ReturnedValue *questions.Question
+	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+	GotAnswerID   *int
+}
+
+func (m *mockQuestionAnswerAccepter) QuestionAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
+}
+
+func (m *mockQuestionAnswerAccepter) QuestionAcceptAnswer(ctx context.Context, id int, answerID *int) (*questions.Question, error) {
+	m.GotAnswerID = answerID
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestAcceptAnswer(t *testing.T) {
+	answerID := 2
+	acceptedID := uint(answerID)
+	tests := []struct {
+		name         string
+		accepter     *mockQuestionAnswerAccepter
+		policy       authz.Policy
+		answerID     *int
+		want         *questions.Question
+		wantAnswerID *int
+		wantErr      *apierror.ApiError
+	}{
+		{
+			name: "accepted",
+			accepter: &mockQuestionAnswerAccepter{
+				ReturnedValue: &questions.Question{
+					Text:             "test",
+					AcceptedAnswerID: &acceptedID,
+				},
+			},
+			policy:   &mockPolicy{Allowed: true},
+			answerID: &answerID,
+			want: &questions.Question{
+				Text:             "test",
+				AcceptedAnswerID: &acceptedID,
+			},
+			wantAnswerID: &answerID,
+			wantErr:      nil,
+		},
+		{
+			name: "unaccepted",
+			accepter: &mockQuestionAnswerAccepter{
+				ReturnedValue: &questions.Question{
+					Text: "test",
+				},
+			},
+			policy:   &mockPolicy{Allowed: true},
+			answerID: nil,
+			want: &questions.Question{
+				Text: "test",
+			},
+			wantAnswerID: nil,
+			wantErr:      nil,
+		},
+		{
+			name:     "forbidden",
+			accepter: &mockQuestionAnswerAccepter{},
+			policy:   &mockPolicy{Allowed: false},
+			answerID: &answerID,
+			want:     nil,
+			wantErr:  utils.Forbidden(nil),
+		},
+		{
+			name: "question not found",
+			accepter: &mockQuestionAnswerAccepter{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:   &mockPolicy{Allowed: true},
+			answerID: &answerID,
+			want:     nil,
+			wantErr:  utils.QuestionNotFound(nil),
+		},
+		{
+			name: "answer of another question",
+			accepter: &mockQuestionAnswerAccepter{
+				ReturnedError: storage.ErrDbAnswerNotFound,
+			},
+			policy:   &mockPolicy{Allowed: true},
+			answerID: &answerID,
+			want:     nil,
+			wantErr:  utils.AnswerNotFound(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := authz.Actor{UserID: uuid.New()}
+			var got *questions.Question
+			var gotErr error
+			if tt.answerID != nil {
+				got, gotErr = AcceptAnswer(context.Background(), tt.accepter, tt.policy, actor, 1, *tt.answerID)
+			} else {
+				got, gotErr = UnacceptAnswer(context.Background(), tt.accepter, tt.policy, actor, 1)
+			}
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("AcceptAnswer() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("AcceptAnswer(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("AcceptAnswer() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("AcceptAnswer() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.wantAnswerID, tt.accepter.GotAnswerID); diff != "" {
+				t.Errorf("AcceptAnswer() answer id mismatch:\n %s", diff)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("AcceptAnswer() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockQuestionRevisionsGetter struct {
+	ReturnedValue []questions.Revision
+	ReturnedError error
+	Trashed       bool
+}
+
+func (m *mockQuestionRevisionsGetter) QuestionRevisionsGet(ctx context.Context, id int) ([]questions.Revision, error) {
+	if m.Trashed {
+		return nil, storage.ErrDbNotFound
+	}
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func (m *mockQuestionRevisionsGetter) QuestionRevisionsGetUnscoped(ctx context.Context, id int) ([]questions.Revision, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestGetQuestionRevisions(t *testing.T) {
+	tests := []struct {
+		name            string
+		revisionsGetter QuestionRevisionsGetter
+		actor           *authz.Actor
+		want            []questions.Revision
+		wantErr         *apierror.ApiError
+	}{
+		{
+			name: "ok",
+			revisionsGetter: &mockQuestionRevisionsGetter{
+				ReturnedValue: []questions.Revision{{ID: 2, Text: "second"}, {ID: 1, Text: "first"}},
+			},
+			actor:   nil,
+			want:    []questions.Revision{{ID: 2, Text: "second"}, {ID: 1, Text: "first"}},
+			wantErr: nil,
+		},
+		{
+			name: "in trash anonymous",
+			revisionsGetter: &mockQuestionRevisionsGetter{
+				ReturnedValue: []questions.Revision{{ID: 1, Text: "first"}},
+				Trashed:       true,
+			},
+			actor:   nil,
+			want:    nil,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "in trash user",
+			revisionsGetter: &mockQuestionRevisionsGetter{
+				ReturnedValue: []questions.Revision{{ID: 1, Text: "first"}},
+				Trashed:       true,
+			},
+			actor:   &authz.Actor{UserID: uuid.New(), Role: users.RoleUser},
+			want:    nil,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "in trash moderator",
+			revisionsGetter: &mockQuestionRevisionsGetter{
+				ReturnedValue: []questions.Revision{{ID: 1, Text: "first"}},
+				Trashed:       true,
+			},
+			actor:   &authz.Actor{UserID: uuid.New(), Role: users.RoleModerator},
+			want:    []questions.Revision{{ID: 1, Text: "first"}},
+			wantErr: nil,
+		},
+		{
+			name: "not found",
+			revisionsGetter: &mockQuestionRevisionsGetter{
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			want:    nil,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "deadline exceeded",
+			revisionsGetter: &mockQuestionRevisionsGetter{
+				ReturnedError: context.DeadlineExceeded,
+			},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := GetQuestionRevisions(context.Background(), tt.revisionsGetter, authz.NewOwnerPolicy(), tt.actor, 1)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("GetQuestionRevisions() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("GetQuestionRevisions(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("GetQuestionRevisions() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("GetQuestionRevisions() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetQuestionRevisions() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockQuestionRollbacker struct {
+	mockQuestionUpdater
+	Revision      *questions.Revision
+	RevisionError error
+	GotDto        *questions.QuestionUpdateDto
+	GotEditorID   uuid.UUID
+}
+
+func (m *mockQuestionRollbacker) QuestionRevisionGet(ctx context.Context, id int, revisionID int) (*questions.Revision, error) {
+	return m.Revision, m.RevisionError
+}
+
+func (m *mockQuestionRollbacker) QuestionUpdate(ctx context.Context, id int, dto *questions.QuestionUpdateDto, editorID uuid.UUID) (*questions.Question, error) {
+	m.GotDto = dto
+	m.GotEditorID = editorID
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestRollbackQuestion(t *testing.T) {
+	tests := []struct {
+		name       string
+		rollbacker *mockQuestionRollbacker
+		dto        *questions.RollbackDto
+		policy     authz.Policy
+		want       *questions.Question
+		wantDto    *questions.QuestionUpdateDto
+		wantErr    *apierror.ApiError
+	}{
+		{
+			name: "rolled back",
+			rollbacker: &mockQuestionRollbacker{
+				mockQuestionUpdater: mockQuestionUpdater{
+					ReturnedValue: &questions.Question{Text: "old"},
+				},
+				Revision: &questions.Revision{ID: 3, Text: "old"},
+			},
+			dto:     &questions.RollbackDto{Reason: "typo"},
+			policy:  &mockPolicy{Allowed: true},
+			want:    &questions.Question{Text: "old"},
+			wantDto: &questions.QuestionUpdateDto{Text: "old", Reason: "typo"},
+			wantErr: nil,
+		},
+		{
+			name: "without reason",
+			rollbacker: &mockQuestionRollbacker{
+				mockQuestionUpdater: mockQuestionUpdater{
+					ReturnedValue: &questions.Question{Text: "old"},
+				},
+				Revision: &questions.Revision{ID: 3, Text: "old"},
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    &questions.Question{Text: "old"},
+			wantDto: &questions.QuestionUpdateDto{Text: "old"},
+			wantErr: nil,
+		},
+		{
+			name: "revision not found",
+			rollbacker: &mockQuestionRollbacker{
+				RevisionError: storage.ErrDbRevisionNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.RevisionNotFound(nil),
+		},
+		{
+			name: "question not found",
+			rollbacker: &mockQuestionRollbacker{
+				mockQuestionUpdater: mockQuestionUpdater{
+					AuthorError: storage.ErrDbNotFound,
+				},
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			rollbacker: &mockQuestionRollbacker{
+				Revision: &questions.Revision{ID: 3, Text: "old"},
+			},
+			policy:  &mockPolicy{Allowed: false},
+			want:    nil,
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := authz.Actor{UserID: uuid.New()}
+			got, gotErr := RollbackQuestion(context.Background(), tt.rollbacker, tt.policy, actor, 1, 3, tt.dto)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("RollbackQuestion() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("RollbackQuestion(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("RollbackQuestion() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("RollbackQuestion() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("RollbackQuestion() mismatch:\n %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDto, tt.rollbacker.GotDto); diff != "" {
+				t.Errorf("RollbackQuestion() dto mismatch:\n %s", diff)
+			}
+			if tt.rollbacker.GotEditorID != actor.UserID {
+				t.Errorf("RollbackQuestion() editor: %v, want: %v", tt.rollbacker.GotEditorID, actor.UserID)
+			}
+		})
+	}
+}
+
+type mockQuestionRestorer struct {
+	ReturnedValue *questions.Question
+	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockQuestionRestorer) QuestionAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
+}
+
+func (m *mockQuestionRestorer) QuestionRestore(ctx context.Context, id int) (*questions.Question, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestRestoreQuestion(t *testing.T) {
+	tests := []struct {
+		name     string
+		restorer QuestionRestorer
+		policy   authz.Policy
+		want     *questions.Question
+		wantErr  *apierror.ApiError
+	}{
+		{
+			name: "restored",
+			restorer: &mockQuestionRestorer{
+				ReturnedValue: &questions.Question{Text: "test"},
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    &questions.Question{Text: "test"},
+			wantErr: nil,
+		},
+		{
+			name: "not in trash",
+			restorer: &mockQuestionRestorer{
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.QuestionNotFoundInTrash(nil),
+		},
+		{
+			name: "question not found",
+			restorer: &mockQuestionRestorer{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			want:    nil,
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name: "forbidden",
+			restorer: &mockQuestionRestorer{
+				ReturnedValue: &questions.Question{Text: "test"},
+			},
+			policy:  &mockPolicy{Allowed: false},
+			want:    nil,
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := RestoreQuestion(context.Background(), tt.restorer, tt.policy, authz.Actor{UserID: uuid.New()}, 1)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("RestoreQuestion() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("RestoreQuestion(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("RestoreQuestion() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("RestoreQuestion() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("RestoreQuestion() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockQuestionPurger struct {
+	ReturnedError error
+	Author        *uuid.UUID
+	AuthorError   error
+}
+
+func (m *mockQuestionPurger) QuestionAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	return m.Author, m.AuthorError
+}
+
+func (m *mockQuestionPurger) QuestionPurge(ctx context.Context, id int) error {
+	return m.ReturnedError
+}
+
+func TestPurgeQuestion(t *testing.T) {
+	tests := []struct {
+		name    string
+		purger  QuestionPurger
+		policy  authz.Policy
+		wantErr *apierror.ApiError
+	}{
+		{
+			name:    "purged",
+			purger:  &mockQuestionPurger{},
+			policy:  &mockPolicy{Allowed: true},
+			wantErr: nil,
+		},
+		{
+			name: "not found",
+			purger: &mockQuestionPurger{
+				AuthorError: storage.ErrDbNotFound,
+			},
+			policy:  &mockPolicy{Allowed: true},
+			wantErr: utils.QuestionNotFound(nil),
+		},
+		{
+			name:    "forbidden",
+			purger:  &mockQuestionPurger{},
+			policy:  &mockPolicy{Allowed: false},
+			wantErr: utils.Forbidden(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotErr := PurgeQuestion(context.Background(), tt.purger, tt.policy, authz.Actor{UserID: uuid.New()}, 1)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("PurgeQuestion() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("PurgeQuestion(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("PurgeQuestion() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("PurgeQuestion() succeeded unexpectedly")
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/search"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+// Searcher is implemented by every storage with its own search engine:
+// tsvector in postgres, fts5 in sqlite and a plain scan in memory.
+type Searcher interface {
+	Search(ctx context.Context, query *search.SearchQuery) (*search.SearchPage, error)
+}
+
+func Search(ctx context.Context, searcher Searcher, query *search.SearchQuery) (*search.SearchPage, error) {
+	ctx, span := tracing.Start(ctx, "search.Search")
+	defer span.End()
+
+	if query == nil || strings.TrimSpace(query.Text) == "" {
+		return nil, utils.EmptySearchQuery(nil)
+	}
+	q := *query
+	if q.Limit <= 0 || q.Limit > search.MaxLimit {
+		q.Limit = search.DefaultLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	page, err := searcher.Search(ctx, &q)
+	if err != nil {
+		return nil, utils.TestDbErr(err)
+	}
+
+	next := q.Offset + len(page.Results)
+	if len(page.Results) > 0 && int64(next) < page.Total {
+		page.NextOffset = &next
+	}
+	return page, nil
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/types/search"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/go-cmp/cmp"
+)
+
+type mockSearcher struct {
+	ReturnedValue *search.SearchPage
+	ReturnedError error
+	GotQuery      *search.SearchQuery
+}
+
+func (m *mockSearcher) Search(ctx context.Context, query *search.SearchQuery) (*search.SearchPage, error) {
+	m.GotQuery = query
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		searcher  *mockSearcher
+		query     *search.SearchQuery
+		wantQuery *search.SearchQuery
+		want      *search.SearchPage
+		wantErr   *apierror.ApiError
+	}{
+		{
+			name: "has next page",
+			searcher: &mockSearcher{
+				ReturnedValue: &search.SearchPage{
+					Results: []search.Result{{ID: 1}, {ID: 2}},
+					Total:   5,
+				},
+			},
+			query:     &search.SearchQuery{Text: "test", Limit: 2, Offset: 2},
+			wantQuery: &search.SearchQuery{Text: "test", Limit: 2, Offset: 2},
+			want: &search.SearchPage{
+				Results:    []search.Result{{ID: 1}, {ID: 2}},
+				Total:      5,
+				NextOffset: intPtr(4),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "last page with default limit",
+			searcher: &mockSearcher{
+				ReturnedValue: &search.SearchPage{
+					Results: []search.Result{{ID: 1}},
+					Total:   1,
+				},
+			},
+			query:     &search.SearchQuery{Text: "test"},
+			wantQuery: &search.SearchQuery{Text: "test", Limit: search.DefaultLimit},
+			want: &search.SearchPage{
+				Results: []search.Result{{ID: 1}},
+				Total:   1,
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "empty text",
+			searcher: &mockSearcher{},
+			query:    &search.SearchQuery{Text: "  "},
+			want:     nil,
+			wantErr:  utils.EmptySearchQuery(nil),
+		},
+		{
+			name: "deadline exceeded",
+			searcher: &mockSearcher{
+				ReturnedValue: nil,
+				ReturnedError: context.DeadlineExceeded,
+			},
+			query:   &search.SearchQuery{Text: "test"},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := Search(context.Background(), tt.searcher, tt.query)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("Search() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("Search(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("Search() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("Search() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.wantQuery, tt.searcher.GotQuery); diff != "" {
+				t.Errorf("Search() query mismatch:\n %s", diff)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Search() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
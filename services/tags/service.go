@@ -0,0 +1,43 @@
+package tags
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/tags"
+	"github.com/gengeo7/highlitent/utils"
+)
+
+type TagsGetter interface {
+	TagsGet(ctx context.Context, query *tags.TagsQuery) ([]tags.TagWithCount, error)
+}
+
+func GetTags(ctx context.Context, tagsGetter TagsGetter, query *tags.TagsQuery) (*tags.TagsPage, error) {
+	ctx, span := tracing.Start(ctx, "tags.GetTags")
+	defer span.End()
+
+	q := tags.TagsQuery{}
+	if query != nil {
+		q = *query
+	}
+	if q.Limit <= 0 || q.Limit > tags.MaxLimit {
+		q.Limit = tags.DefaultLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	// one extra tag is requested to know if there is a next page
+	ts, err := tagsGetter.TagsGet(ctx, &tags.TagsQuery{Limit: q.Limit + 1, Offset: q.Offset})
+	if err != nil {
+		return nil, utils.TestDbErr(err)
+	}
+
+	page := &tags.TagsPage{Tags: ts}
+	if len(ts) > q.Limit {
+		page.Tags = ts[:q.Limit]
+		next := q.Offset + q.Limit
+		page.NextOffset = &next
+	}
+	return page, nil
+}
@@ -0,0 +1,103 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/types/tags"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/go-cmp/cmp"
+)
+
+type mockTagsGetter struct {
+	ReturnedValue []tags.TagWithCount
+	ReturnedError error
+	GotQuery      *tags.TagsQuery
+}
+
+func (m *mockTagsGetter) TagsGet(ctx context.Context, query *tags.TagsQuery) ([]tags.TagWithCount, error) {
+	m.GotQuery = query
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestGetTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		tagsGetter *mockTagsGetter
+		query      *tags.TagsQuery
+		wantQuery  *tags.TagsQuery
+		want       *tags.TagsPage
+		wantErr    *apierror.ApiError
+	}{
+		{
+			name: "has next page",
+			tagsGetter: &mockTagsGetter{
+				ReturnedValue: []tags.TagWithCount{{Name: "go", Questions: 3}, {Name: "sql", Questions: 2}, {Name: "c++", Questions: 1}},
+			},
+			query:     &tags.TagsQuery{Limit: 2, Offset: 2},
+			wantQuery: &tags.TagsQuery{Limit: 3, Offset: 2},
+			want: &tags.TagsPage{
+				Tags:       []tags.TagWithCount{{Name: "go", Questions: 3}, {Name: "sql", Questions: 2}},
+				NextOffset: intPtr(4),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "last page with default query",
+			tagsGetter: &mockTagsGetter{
+				ReturnedValue: []tags.TagWithCount{{Name: "go", Questions: 1}},
+			},
+			query:     nil,
+			wantQuery: &tags.TagsQuery{Limit: tags.DefaultLimit + 1},
+			want: &tags.TagsPage{
+				Tags: []tags.TagWithCount{{Name: "go", Questions: 1}},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "deadline exceeded",
+			tagsGetter: &mockTagsGetter{
+				ReturnedError: context.DeadlineExceeded,
+			},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := GetTags(context.Background(), tt.tagsGetter, tt.query)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("GetTags() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("GetTags(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("GetTags() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("GetTags() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.wantQuery, tt.tagsGetter.GotQuery); diff != "" {
+				t.Errorf("GetTags() query mismatch:\n %s", diff)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetTags() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
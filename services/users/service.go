@@ -0,0 +1,204 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/logger"
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/tracing"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserCreater interface {
+	UserCreate(ctx context.Context, dto *users.UserDto, passwordHash string) (*users.User, error)
+}
+
+type UserGetter interface {
+	UserGet(ctx context.Context, id uuid.UUID) (*users.User, error)
+}
+
+type UserByEmailGetter interface {
+	UserGetByEmail(ctx context.Context, email string) (*users.User, error)
+}
+
+type UserRoleSetter interface {
+	UserSetRole(ctx context.Context, id uuid.UUID, role users.Role) (*users.User, error)
+}
+
+type UserRegisterer interface {
+	UserCreater
+	UserRoleSetter
+}
+
+type AdminPromoter interface {
+	UserByEmailGetter
+	UserRoleSetter
+}
+
+type TokenIssuer interface {
+	Issue(userID uuid.UUID) (string, time.Time, error)
+}
+
+type UsersGetter interface {
+	UsersGet(ctx context.Context, query *users.UsersQuery) ([]users.User, error)
+}
+
+// RegisterUser creates the user, the user with adminEmail is made an
+// admin. adminEmail is empty when no admin is set up.
+func RegisterUser(ctx context.Context, registerer UserRegisterer, dto *users.UserDto, adminEmail string) (*users.User, error) {
+	ctx, span := tracing.Start(ctx, "users.RegisterUser")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(dto.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, utils.UnhandledError(err)
+	}
+
+	normalized := *dto
+	normalized.Email = strings.ToLower(strings.TrimSpace(dto.Email))
+	user, err := registerer.UserCreate(ctx, &normalized, string(hash))
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrAlreadyExists, Creator: utils.UserAlreadyExists, CheckErr: false})
+	}
+	logger.InfoCtx(ctx, "user registered", "user_id", user.ID)
+
+	if adminEmail != "" && user.Email == adminEmail {
+		user, err = registerer.UserSetRole(ctx, user.ID, users.RoleAdmin)
+		if err != nil {
+			return nil, utils.TestDbErr(err)
+		}
+		logger.WarnCtx(ctx, "user promoted to admin by ADMIN_EMAIL", "user_id", user.ID)
+	}
+	return user, nil
+}
+
+// PromoteAdmin makes the registered user with email an admin, it runs
+// at startup. A user not registered yet is promoted by RegisterUser.
+func PromoteAdmin(ctx context.Context, promoter AdminPromoter, email string) error {
+	ctx, span := tracing.Start(ctx, "users.PromoteAdmin")
+	defer span.End()
+
+	if email == "" {
+		return nil
+	}
+	user, err := promoter.UserGetByEmail(ctx, email)
+	if err != nil {
+		if storage.IsErrNotFound(err) {
+			logger.InfoCtx(ctx, "ADMIN_EMAIL is not registered yet, it is promoted on registration")
+			return nil
+		}
+		return utils.TestDbErr(err)
+	}
+	if user.Role == users.RoleAdmin {
+		return nil
+	}
+	if _, err := promoter.UserSetRole(ctx, user.ID, users.RoleAdmin); err != nil {
+		return utils.TestDbErr(err)
+	}
+	logger.WarnCtx(ctx, "user promoted to admin by ADMIN_EMAIL", "user_id", user.ID)
+	return nil
+}
+
+// SetRole changes the role of the user with id, it is left to admins.
+func SetRole(ctx context.Context, roleSetter UserRoleSetter, policy authz.Policy, actor authz.Actor, id uuid.UUID, dto *users.RoleDto) (*users.User, error) {
+	ctx, span := tracing.Start(ctx, "users.SetRole")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	if !policy.Can(actor, authz.ActionManageRoles, &id) {
+		logger.WarnCtx(ctx, "action forbidden", "action", authz.ActionManageRoles)
+		return nil, utils.Forbidden(nil)
+	}
+	user, err := roleSetter.UserSetRole(ctx, id, dto.Role)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.UserNotFound, CheckErr: false})
+	}
+	// logged at warn so the change is seen at any level
+	logger.WarnCtx(ctx, "user role changed", "user_id", id, "role", dto.Role)
+	return user, nil
+}
+
+func Login(ctx context.Context, userGetter UserByEmailGetter, tokenIssuer TokenIssuer, dto *users.LoginDto) (*users.TokenDto, error) {
+	ctx, span := tracing.Start(ctx, "users.Login")
+	defer span.End()
+
+	if dto == nil {
+		return nil, utils.EmptyDto(nil)
+	}
+	user, err := userGetter.UserGetByEmail(ctx, strings.ToLower(strings.TrimSpace(dto.Email)))
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.InvalidCredentials, CheckErr: false})
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(dto.Password))
+	if err != nil {
+		logger.WarnCtx(ctx, "login with wrong password", "user_id", user.ID)
+		return nil, utils.InvalidCredentials(nil)
+	}
+
+	token, expiresAt, err := tokenIssuer.Issue(user.ID)
+	if err != nil {
+		return nil, utils.UnhandledError(err)
+	}
+	return &users.TokenDto{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// GetUser returns the user with the email to the user and admins, and
+// users.PublicUser to everyone else. actor is nil for anonymous
+// requests.
+func GetUser(ctx context.Context, userGetter UserGetter, policy authz.Policy, actor *authz.Actor, id uuid.UUID) (any, error) {
+	ctx, span := tracing.Start(ctx, "users.GetUser")
+	defer span.End()
+
+	user, err := userGetter.UserGet(ctx, id)
+	if err != nil {
+		return nil, utils.TestDbErr(err, &utils.ErrDbCase{Func: storage.IsErrNotFound, Creator: utils.UserNotFound, CheckErr: false})
+	}
+	if actor != nil && policy.Can(*actor, authz.ActionViewEmail, &user.ID) {
+		return user, nil
+	}
+	return user.Public(), nil
+}
+
+func GetUsers(ctx context.Context, usersGetter UsersGetter, query *users.UsersQuery) (*users.UsersPage, error) {
+	ctx, span := tracing.Start(ctx, "users.GetUsers")
+	defer span.End()
+
+	q := users.UsersQuery{}
+	if query != nil {
+		q = *query
+	}
+	if q.Limit <= 0 || q.Limit > users.MaxLimit {
+		q.Limit = users.DefaultLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	// one extra user is requested to know if there is a next page
+	us, err := usersGetter.UsersGet(ctx, &users.UsersQuery{Limit: q.Limit + 1, Offset: q.Offset})
+	if err != nil {
+		return nil, utils.TestDbErr(err)
+	}
+
+	page := &users.UsersPage{Users: make([]users.PublicUser, 0, len(us))}
+	if len(us) > q.Limit {
+		us = us[:q.Limit]
+		next := q.Offset + q.Limit
+		page.NextOffset = &next
+	}
+	for _, u := range us {
+		page.Users = append(page.Users, u.Public())
+	}
+	return page, nil
+}
@@ -0,0 +1,562 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/authz"
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/gengeo7/highlitent/utils"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type mockUserCreater struct {
+	ReturnedError   error
+	GotDto          *users.UserDto
+	GotPasswordHash string
+}
+
+func (m *mockUserCreater) UserCreate(ctx context.Context, dto *users.UserDto, passwordHash string) (*users.User, error) {
+	m.GotDto = dto
+	m.GotPasswordHash = passwordHash
+	if m.ReturnedError != nil {
+		return nil, m.ReturnedError
+	}
+	return &users.User{Name: dto.Name, Email: dto.Email, PasswordHash: passwordHash, Role: users.RoleUser}, nil
+}
+
+func (m *mockUserCreater) UserSetRole(ctx context.Context, id uuid.UUID, role users.Role) (*users.User, error) {
+	return &users.User{ID: id, Name: m.GotDto.Name, Email: m.GotDto.Email, PasswordHash: m.GotPasswordHash, Role: role}, nil
+}
+
+func TestRegisterUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		userCreater *mockUserCreater
+		dto         *users.UserDto
+		adminEmail  string
+		wantEmail   string
+		wantRole    users.Role
+		wantErr     *apierror.ApiError
+	}{
+		{
+			name:        "registered",
+			userCreater: &mockUserCreater{},
+			dto: &users.UserDto{
+				Name:     "test",
+				Email:    " Test@Email.com",
+				Password: "12345678",
+			},
+			adminEmail: "admin@email.com",
+			wantEmail:  "test@email.com",
+			wantRole:   users.RoleUser,
+			wantErr:    nil,
+		},
+		{
+			name:        "admin email",
+			userCreater: &mockUserCreater{},
+			dto: &users.UserDto{
+				Name:     "admin",
+				Email:    "Admin@Email.com",
+				Password: "12345678",
+			},
+			adminEmail: "admin@email.com",
+			wantEmail:  "admin@email.com",
+			wantRole:   users.RoleAdmin,
+			wantErr:    nil,
+		},
+		{
+			name:        "empty dto",
+			userCreater: &mockUserCreater{},
+			dto:         nil,
+			wantErr:     utils.EmptyDto(nil),
+		},
+		{
+			name: "email taken",
+			userCreater: &mockUserCreater{
+				ReturnedError: storage.ErrDbAlreadyExists,
+			},
+			dto: &users.UserDto{
+				Name:     "test",
+				Email:    "test@email.com",
+				Password: "12345678",
+			},
+			wantErr: utils.UserAlreadyExists(nil),
+		},
+		{
+			name: "deadline exceeded",
+			userCreater: &mockUserCreater{
+				ReturnedError: context.DeadlineExceeded,
+			},
+			dto: &users.UserDto{
+				Name:     "test",
+				Email:    "test@email.com",
+				Password: "12345678",
+			},
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := RegisterUser(context.Background(), tt.userCreater, tt.dto, tt.adminEmail)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("RegisterUser() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("RegisterUser(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("RegisterUser() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("RegisterUser() succeeded unexpectedly")
+			}
+
+			if got.Email != tt.wantEmail {
+				t.Errorf("RegisterUser() email: %q, want: %q", got.Email, tt.wantEmail)
+			}
+			if got.Role != tt.wantRole {
+				t.Errorf("RegisterUser() role: %q, want: %q", got.Role, tt.wantRole)
+			}
+			if err := bcrypt.CompareHashAndPassword([]byte(tt.userCreater.GotPasswordHash), []byte(tt.dto.Password)); err != nil {
+				t.Errorf("RegisterUser() password hash mismatch: %v", err)
+			}
+		})
+	}
+}
+
+type mockUserByEmailGetter struct {
+	ReturnedValue *users.User
+	ReturnedError error
+}
+
+func (m *mockUserByEmailGetter) UserGetByEmail(ctx context.Context, email string) (*users.User, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+type mockTokenIssuer struct{}
+
+func (m *mockTokenIssuer) Issue(userID uuid.UUID) (string, time.Time, error) {
+	return userID.String(), time.Time{}, nil
+}
+
+func TestLogin(t *testing.T) {
+	userID := uuid.New()
+	hash, err := bcrypt.GenerateFromPassword([]byte("12345678"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() failed: %v", err)
+	}
+	user := &users.User{ID: userID, PasswordHash: string(hash)}
+
+	tests := []struct {
+		name       string
+		userGetter UserByEmailGetter
+		dto        *users.LoginDto
+		want       *users.TokenDto
+		wantErr    *apierror.ApiError
+	}{
+		{
+			name:       "ok",
+			userGetter: &mockUserByEmailGetter{ReturnedValue: user},
+			dto:        &users.LoginDto{Email: "test@email.com", Password: "12345678"},
+			want:       &users.TokenDto{Token: userID.String()},
+			wantErr:    nil,
+		},
+		{
+			name:       "wrong password",
+			userGetter: &mockUserByEmailGetter{ReturnedValue: user},
+			dto:        &users.LoginDto{Email: "test@email.com", Password: "87654321"},
+			want:       nil,
+			wantErr:    utils.InvalidCredentials(nil),
+		},
+		{
+			name:       "unknown email",
+			userGetter: &mockUserByEmailGetter{ReturnedError: storage.ErrDbNotFound},
+			dto:        &users.LoginDto{Email: "test@email.com", Password: "12345678"},
+			want:       nil,
+			wantErr:    utils.InvalidCredentials(nil),
+		},
+		{
+			name:       "empty dto",
+			userGetter: &mockUserByEmailGetter{},
+			dto:        nil,
+			want:       nil,
+			wantErr:    utils.EmptyDto(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := Login(context.Background(), tt.userGetter, &mockTokenIssuer{}, tt.dto)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("Login() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("Login(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("Login() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("Login() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Login() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockUserGetter struct {
+	ReturnedValue *users.User
+	ReturnedError error
+}
+
+func (m *mockUserGetter) UserGet(ctx context.Context, id uuid.UUID) (*users.User, error) {
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func TestGetUser(t *testing.T) {
+	userID := uuid.New()
+	user := &users.User{ID: userID, Name: "test", Email: "test@email.com", Role: users.RoleUser}
+	tests := []struct {
+		name       string
+		userGetter UserGetter
+		actor      *authz.Actor
+		want       any
+		wantErr    *apierror.ApiError
+	}{
+		{
+			name:       "anonymous",
+			userGetter: &mockUserGetter{ReturnedValue: user},
+			actor:      nil,
+			want:       users.PublicUser{ID: userID, Name: "test", Role: users.RoleUser},
+			wantErr:    nil,
+		},
+		{
+			name:       "other user",
+			userGetter: &mockUserGetter{ReturnedValue: user},
+			actor:      &authz.Actor{UserID: uuid.New(), Role: users.RoleModerator},
+			want:       users.PublicUser{ID: userID, Name: "test", Role: users.RoleUser},
+			wantErr:    nil,
+		},
+		{
+			name:       "same user",
+			userGetter: &mockUserGetter{ReturnedValue: user},
+			actor:      &authz.Actor{UserID: userID, Role: users.RoleUser},
+			want:       user,
+			wantErr:    nil,
+		},
+		{
+			name:       "admin",
+			userGetter: &mockUserGetter{ReturnedValue: user},
+			actor:      &authz.Actor{UserID: uuid.New(), Role: users.RoleAdmin},
+			want:       user,
+			wantErr:    nil,
+		},
+		{
+			name: "not found",
+			userGetter: &mockUserGetter{
+				ReturnedError: storage.ErrDbNotFound,
+			},
+			want:    nil,
+			wantErr: utils.UserNotFound(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := GetUser(context.Background(), tt.userGetter, authz.NewOwnerPolicy(), tt.actor, userID)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("GetUser() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("GetUser(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("GetUser() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("GetUser() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetUser() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockUsersGetter struct {
+	ReturnedValue []users.User
+	ReturnedError error
+	GotQuery      *users.UsersQuery
+}
+
+func (m *mockUsersGetter) UsersGet(ctx context.Context, query *users.UsersQuery) ([]users.User, error) {
+	m.GotQuery = query
+	return m.ReturnedValue, m.ReturnedError
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestGetUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		usersGetter *mockUsersGetter
+		query       *users.UsersQuery
+		wantQuery   *users.UsersQuery
+		want        *users.UsersPage
+		wantErr     *apierror.ApiError
+	}{
+		{
+			name: "has next page",
+			usersGetter: &mockUsersGetter{
+				ReturnedValue: []users.User{{Name: "1", Email: "1@email.com"}, {Name: "2", Email: "2@email.com"}, {Name: "3", Email: "3@email.com"}},
+			},
+			query:     &users.UsersQuery{Limit: 2, Offset: 4},
+			wantQuery: &users.UsersQuery{Limit: 3, Offset: 4},
+			want: &users.UsersPage{
+				Users:      []users.PublicUser{{Name: "1"}, {Name: "2"}},
+				NextOffset: intPtr(6),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "last page with default query",
+			usersGetter: &mockUsersGetter{
+				ReturnedValue: []users.User{{Name: "1"}},
+			},
+			query:     nil,
+			wantQuery: &users.UsersQuery{Limit: users.DefaultLimit + 1},
+			want: &users.UsersPage{
+				Users: []users.PublicUser{{Name: "1"}},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "deadline exceeded",
+			usersGetter: &mockUsersGetter{
+				ReturnedError: context.DeadlineExceeded,
+			},
+			want:    nil,
+			wantErr: utils.DeadlineDbError(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := GetUsers(context.Background(), tt.usersGetter, tt.query)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("GetUsers() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("GetUsers(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("GetUsers() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("GetUsers() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.wantQuery, tt.usersGetter.GotQuery); diff != "" {
+				t.Errorf("GetUsers() query mismatch:\n %s", diff)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetUsers() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+type mockUserRoleSetter struct {
+	mockUserByEmailGetter
+	ReturnedError error
+	GotID         uuid.UUID
+	GotRole       users.Role
+}
+
+func (m *mockUserRoleSetter) UserSetRole(ctx context.Context, id uuid.UUID, role users.Role) (*users.User, error) {
+	m.GotID = id
+	m.GotRole = role
+	if m.ReturnedError != nil {
+		return nil, m.ReturnedError
+	}
+	return &users.User{ID: id, Role: role}, nil
+}
+
+func TestSetRole(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		roleSetter *mockUserRoleSetter
+		actor      authz.Actor
+		dto        *users.RoleDto
+		want       *users.User
+		wantErr    *apierror.ApiError
+	}{
+		{
+			name:       "admin",
+			roleSetter: &mockUserRoleSetter{},
+			actor:      authz.Actor{UserID: uuid.New(), Role: users.RoleAdmin},
+			dto:        &users.RoleDto{Role: users.RoleModerator},
+			want:       &users.User{ID: userID, Role: users.RoleModerator},
+			wantErr:    nil,
+		},
+		{
+			name:       "moderator",
+			roleSetter: &mockUserRoleSetter{},
+			actor:      authz.Actor{UserID: uuid.New(), Role: users.RoleModerator},
+			dto:        &users.RoleDto{Role: users.RoleModerator},
+			want:       nil,
+			wantErr:    utils.Forbidden(nil),
+		},
+		{
+			name:       "user promoting self",
+			roleSetter: &mockUserRoleSetter{},
+			actor:      authz.Actor{UserID: userID, Role: users.RoleUser},
+			dto:        &users.RoleDto{Role: users.RoleAdmin},
+			want:       nil,
+			wantErr:    utils.Forbidden(nil),
+		},
+		{
+			name:       "not found",
+			roleSetter: &mockUserRoleSetter{ReturnedError: storage.ErrDbNotFound},
+			actor:      authz.Actor{UserID: uuid.New(), Role: users.RoleAdmin},
+			dto:        &users.RoleDto{Role: users.RoleModerator},
+			want:       nil,
+			wantErr:    utils.UserNotFound(nil),
+		},
+		{
+			name:       "empty dto",
+			roleSetter: &mockUserRoleSetter{},
+			actor:      authz.Actor{UserID: uuid.New(), Role: users.RoleAdmin},
+			dto:        nil,
+			want:       nil,
+			wantErr:    utils.EmptyDto(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := SetRole(context.Background(), tt.roleSetter, authz.NewOwnerPolicy(), tt.actor, userID, tt.dto)
+			if gotErr != nil {
+				if tt.wantErr == nil {
+					t.Fatalf("SetRole() failed: %v", gotErr)
+				}
+				var gotApiError *apierror.ApiError
+				if errors.As(gotErr, &gotApiError) {
+					if gotApiError.Code != tt.wantErr.Code || gotApiError.Msg != tt.wantErr.Msg || gotApiError.StatusCode != tt.wantErr.StatusCode {
+						t.Fatalf("SetRole(): %v, want: %v", gotErr, tt.wantErr)
+					}
+				} else {
+					t.Fatalf("SetRole() expected error of type ApiError: %v", gotErr)
+				}
+				return
+			}
+
+			if tt.wantErr != nil {
+				t.Fatal("SetRole() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SetRole() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestPromoteAdmin(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name     string
+		promoter *mockUserRoleSetter
+		email    string
+		wantRole users.Role
+		wantErr  bool
+	}{
+		{
+			name: "promoted",
+			promoter: &mockUserRoleSetter{
+				mockUserByEmailGetter: mockUserByEmailGetter{ReturnedValue: &users.User{ID: userID, Role: users.RoleUser}},
+			},
+			email:    "admin@email.com",
+			wantRole: users.RoleAdmin,
+		},
+		{
+			name: "already admin",
+			promoter: &mockUserRoleSetter{
+				mockUserByEmailGetter: mockUserByEmailGetter{ReturnedValue: &users.User{ID: userID, Role: users.RoleAdmin}},
+			},
+			email:    "admin@email.com",
+			wantRole: "",
+		},
+		{
+			name: "not registered",
+			promoter: &mockUserRoleSetter{
+				mockUserByEmailGetter: mockUserByEmailGetter{ReturnedError: storage.ErrDbNotFound},
+			},
+			email:    "admin@email.com",
+			wantRole: "",
+		},
+		{
+			name:     "no admin email",
+			promoter: &mockUserRoleSetter{},
+			email:    "",
+			wantRole: "",
+		},
+		{
+			name: "deadline exceeded",
+			promoter: &mockUserRoleSetter{
+				mockUserByEmailGetter: mockUserByEmailGetter{ReturnedError: context.DeadlineExceeded},
+			},
+			email:   "admin@email.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotErr := PromoteAdmin(context.Background(), tt.promoter, tt.email)
+			if (gotErr != nil) != tt.wantErr {
+				t.Fatalf("PromoteAdmin() error: %v, want error: %v", gotErr, tt.wantErr)
+			}
+			if tt.promoter.GotRole != tt.wantRole {
+				t.Errorf("PromoteAdmin() set role: %q, want: %q", tt.promoter.GotRole, tt.wantRole)
+			}
+			if tt.wantRole != "" && tt.promoter.GotID != userID {
+				t.Errorf("PromoteAdmin() set role of %v, want: %v", tt.promoter.GotID, userID)
+			}
+		})
+	}
+}
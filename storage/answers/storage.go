@@ -4,10 +4,20 @@ import (
 	"context"
 
 	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/google/uuid"
 )
 
 type Storage interface {
 	AnswerGet(ctx context.Context, id int) (*answers.Answer, error)
-	AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int) (*answers.Answer, error)
+	AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int, userID uuid.UUID) (*answers.Answer, error)
+	AnswerAuthor(ctx context.Context, id int) (*uuid.UUID, error)
+	AnswerAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error)
+	AnswerUpdate(ctx context.Context, id int, dto *answers.AnswerUpdateDto, editorID uuid.UUID) (*answers.Answer, error)
+	AnswerRevisionsGet(ctx context.Context, id int) ([]answers.Revision, error)
+	AnswerRevisionsGetUnscoped(ctx context.Context, id int) ([]answers.Revision, error)
+	AnswerRevisionGet(ctx context.Context, id int, revisionID int) (*answers.Revision, error)
 	AnswerDelete(ctx context.Context, id int) error
+	AnswerRestore(ctx context.Context, id int) (*answers.Answer, error)
+	AnswerPurge(ctx context.Context, id int) error
+	AnswerVote(ctx context.Context, id int, userID uuid.UUID, value int) (*answers.Answer, error)
 }
@@ -0,0 +1,13 @@
+package comments
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/google/uuid"
+)
+
+type Storage interface {
+	CommentCreate(ctx context.Context, dto *comments.CommentDto, answerID int, userID uuid.UUID) (*comments.Comment, error)
+	CommentsGet(ctx context.Context, answerID int) ([]comments.Comment, error)
+}
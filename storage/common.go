@@ -6,13 +6,38 @@ import (
 )
 
 var (
-	ErrDbNotFound error = errors.New("not found in db")
+	ErrDbNotFound         error = errors.New("not found in db")
+	ErrDbUserNotFound     error = errors.New("user not found in db")
+	ErrDbAnswerNotFound   error = errors.New("answer not found in db")
+	ErrDbCommentNotFound  error = errors.New("comment not found in db")
+	ErrDbRevisionNotFound error = errors.New("revision not found in db")
+	ErrDbAlreadyExists    error = errors.New("already exists in db")
 )
 
 func IsErrNotFound(err error) bool {
 	return errors.Is(err, ErrDbNotFound)
 }
 
+func IsErrUserNotFound(err error) bool {
+	return errors.Is(err, ErrDbUserNotFound)
+}
+
+func IsErrAnswerNotFound(err error) bool {
+	return errors.Is(err, ErrDbAnswerNotFound)
+}
+
+func IsErrCommentNotFound(err error) bool {
+	return errors.Is(err, ErrDbCommentNotFound)
+}
+
+func IsErrRevisionNotFound(err error) bool {
+	return errors.Is(err, ErrDbRevisionNotFound)
+}
+
+func IsErrAlreadyExists(err error) bool {
+	return errors.Is(err, ErrDbAlreadyExists)
+}
+
 func IsErrDeadline(err error) bool {
 	return errors.Is(err, context.DeadlineExceeded)
 }
@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/gengeo7/highlitent/storage"
 	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (d *Db) AnswerGet(ctx context.Context, id int) (*answers.Answer, error) {
@@ -25,14 +30,39 @@ func (d *Db) AnswerGet(ctx context.Context, id int) (*answers.Answer, error) {
 	return &a, nil
 }
 
-func (d *Db) AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int) (*answers.Answer, error) {
+func (d *Db) AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int, userID uuid.UUID) (*answers.Answer, error) {
 	a := &answers.Answer{
 		QuestionID: questionID,
-		UserID:     dto.UserID,
+		UserID:     userID,
 		Text:       dto.Text,
 	}
 
-	err := d.Db.WithContext(ctx).Create(a).Error
+	// answers.user_id has no foreign key, because answers created before
+	// the users table have arbitrary user ids, so the user is checked here
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the foreign key lets answers to questions in the trash through
+		var count int64
+		err := tx.Model(&questions.Question{}).
+			Where("id = ?", questionID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return storage.ErrDbNotFound
+		}
+
+		err = tx.Model(&users.User{}).
+			Where("id = ?", userID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return storage.ErrDbUserNotFound
+		}
+		return tx.Create(a).Error
+	})
 	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
 			return nil, storage.ErrDbNotFound
@@ -42,7 +72,134 @@ func (d *Db) AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionI
 	return a, nil
 }
 
+func (d *Db) AnswerAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	return answerAuthor(d.Db.WithContext(ctx), id)
+}
+
+// AnswerAuthorUnscoped finds answers in the trash as well, so restoring
+// and purging them can be authorized.
+func (d *Db) AnswerAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	return answerAuthor(d.Db.WithContext(ctx).Unscoped(), id)
+}
+
+func answerAuthor(db *gorm.DB, id int) (*uuid.UUID, error) {
+	var a answers.Answer
+	err := db.
+		Select("user_id").
+		Where("id = ?", id).
+		Take(&a).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.ErrDbNotFound
+		}
+		return nil, err
+	}
+	return &a.UserID, nil
+}
+
+func (d *Db) AnswerUpdate(ctx context.Context, id int, dto *answers.AnswerUpdateDto, editorID uuid.UUID) (*answers.Answer, error) {
+	var a answers.Answer
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the row is locked so concurrent edits save
+		// the text each of them replaced
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&a).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrDbNotFound
+			}
+			return err
+		}
+		// an edit with the same text saves no revision,
+		// but still bumps updated_at
+		if a.Text != dto.Text {
+			err = tx.Create(&answers.Revision{
+				AnswerID: a.ID,
+				Text:     a.Text,
+				EditorID: &editorID,
+				Reason:   dto.Reason,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Model(&a).Update("text", dto.Text).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).First(&a).Error
+	})
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+			return nil, storage.ErrDbUserNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// AnswerDelete moves the answer to the trash. An accepted answer
+// stops being accepted and stays so after a restore.
 func (d *Db) AnswerDelete(ctx context.Context, id int) error {
+	return d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&answers.Answer{}).
+			Where("id = ?", id).
+			UpdateColumn("deleted_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return storage.ErrDbNotFound
+		}
+
+		return tx.Model(&questions.Question{}).
+			Where("accepted_answer_id = ?", id).
+			UpdateColumn("accepted_answer_id", nil).Error
+	})
+}
+
+// AnswerRestore takes the answer out of the trash. An answer whose
+// question is in the trash is restored only with the question.
+func (d *Db) AnswerRestore(ctx context.Context, id int) (*answers.Answer, error) {
+	var a answers.Answer
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Where("id = ? and deleted_at is not null", id).
+			First(&a).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrDbNotFound
+			}
+			return err
+		}
+
+		var count int64
+		err = tx.Model(&questions.Question{}).
+			Where("id = ?", a.QuestionID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return storage.ErrDbNotFound
+		}
+
+		err = tx.Unscoped().Model(&a).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).First(&a).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// AnswerPurge deletes the answer for good, whether it is in the trash
+// or not. Its votes and comments go with it.
+func (d *Db) AnswerPurge(ctx context.Context, id int) error {
 	res := d.Db.WithContext(ctx).Unscoped().
 		Delete(&answers.Answer{}, "id = ?", id)
 	if res.Error != nil {
@@ -55,3 +212,45 @@ func (d *Db) AnswerDelete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (d *Db) AnswerVote(ctx context.Context, id int, userID uuid.UUID, value int) (*answers.Answer, error) {
+	var a answers.Answer
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the answer row is locked so concurrent votes recount
+		// the score one after another
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&a).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrDbNotFound
+			}
+			return err
+		}
+
+		v := &answers.Vote{AnswerID: a.ID, UserID: userID, Value: value}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "answer_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(v).Error
+		if err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+				return storage.ErrDbUserNotFound
+			}
+			return err
+		}
+
+		score := tx.Model(&answers.Vote{}).
+			Select("coalesce(sum(value), 0)").
+			Where("answer_id = ?", a.ID)
+		err = tx.Model(&a).UpdateColumn("score", score).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id = ?", a.ID).First(&a).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
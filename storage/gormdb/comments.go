@@ -0,0 +1,79 @@
+package gormdb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (d *Db) CommentCreate(ctx context.Context, dto *comments.CommentDto, answerID int, userID uuid.UUID) (*comments.Comment, error) {
+	c := &comments.Comment{
+		AnswerID: answerID,
+		ParentID: dto.ParentID,
+		UserID:   userID,
+		Text:     dto.Text,
+	}
+
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&answers.Answer{}).
+			Where("id = ?", answerID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return storage.ErrDbNotFound
+		}
+
+		// a reply must stay under the same answer as its parent
+		if dto.ParentID != nil {
+			err := tx.Model(&comments.Comment{}).
+				Where("id = ? and answer_id = ?", *dto.ParentID, answerID).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return storage.ErrDbCommentNotFound
+			}
+		}
+		return tx.Create(c).Error
+	})
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+			return nil, storage.ErrDbUserNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (d *Db) CommentsGet(ctx context.Context, answerID int) ([]comments.Comment, error) {
+	var count int64
+	err := d.Db.WithContext(ctx).
+		Model(&answers.Answer{}).
+		Where("id = ?", answerID).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, storage.ErrDbNotFound
+	}
+
+	cs := make([]comments.Comment, 0)
+	err = d.Db.WithContext(ctx).
+		Where("answer_id = ?", answerID).
+		Order("created_at asc, id asc").
+		Find(&cs).Error
+	if err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
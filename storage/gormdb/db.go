@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/gengeo7/highlitent/types/common"
 	"github.com/pressly/goose/v3"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -13,6 +14,9 @@ import (
 type Db struct {
 	Db    *gorm.DB
 	SqlDb *sql.DB
+	// MigrationPath is kept after Migrate to check later that the
+	// database is at the latest migration
+	MigrationPath string
 }
 
 func NewDb() *Db {
@@ -40,7 +44,7 @@ func (d *Db) Open(config *DsnConfig) error {
 	var err error
 
 	for range 30 {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: NewLogger()})
 		if err == nil {
 			break
 		}
@@ -64,10 +68,44 @@ func (d *Db) Open(config *DsnConfig) error {
 	return nil
 }
 
+func (d *Db) Close() error {
+	return d.SqlDb.Close()
+}
+
+// paginate applies keyset pagination on (created_at, id). One extra row is
+// requested so the caller can tell whether there is a next page.
+func paginate(tx *gorm.DB, cursor *common.Cursor, order common.Order, limit int) *gorm.DB {
+	if order == common.OrderAsc {
+		if cursor != nil {
+			tx = tx.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+		}
+		tx = tx.Order("created_at asc, id asc")
+	} else {
+		if cursor != nil {
+			tx = tx.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+		}
+		tx = tx.Order("created_at desc, id desc")
+	}
+	return tx.Limit(limit + 1)
+}
+
+// paginateByScore is paginate for answers sorted by score desc,
+// answers with the same score go from oldest to newest.
+func paginateByScore(tx *gorm.DB, cursor *common.Cursor, limit int) *gorm.DB {
+	if cursor != nil && cursor.Score != nil {
+		tx = tx.Where(
+			"score < ? or (score = ? and (created_at, id) > (?, ?))",
+			*cursor.Score, *cursor.Score, cursor.CreatedAt, cursor.ID,
+		)
+	}
+	return tx.Order("score desc, created_at asc, id asc").Limit(limit + 1)
+}
+
 func (d *Db) Migrate(migrationPath string) error {
 	err := goose.SetDialect("postgres")
 	if err != nil {
 		return err
 	}
+	d.MigrationPath = migrationPath
 	return goose.Up(d.SqlDb, migrationPath)
 }
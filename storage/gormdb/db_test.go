@@ -0,0 +1,103 @@
+package gormdb
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gengeo7/highlitent/storage/storagetest"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/search"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// openTestDb connects to the postgres of TEST_POSTGRES_DSN, for example
+// "host=localhost user=postgres password=postgres dbname=highlitent sslmode=disable".
+// Every test migrates its own schema, which is dropped after the test.
+// The tests are skipped without TEST_POSTGRES_DSN.
+func openTestDb(t *testing.T) *Db {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open() failed: %v", err)
+	}
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err := admin.Exec("create schema " + schema).Error; err != nil {
+		t.Fatalf("create schema failed: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("drop schema " + schema + " cascade")
+		if sqlDb, err := admin.DB(); err == nil {
+			sqlDb.Close()
+		}
+	})
+
+	db := NewDb()
+	db.Db, err = gorm.Open(postgres.Open(dsn+" TimeZone=UTC search_path="+schema), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open() failed: %v", err)
+	}
+	db.SqlDb, err = db.Db.DB()
+	if err != nil {
+		t.Fatalf("DB() failed: %v", err)
+	}
+	t.Cleanup(func() { db.SqlDb.Close() })
+	if err := db.Migrate("../../migrations"); err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	return db
+}
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return openTestDb(t)
+	})
+}
+
+// TestSearchRussian checks what only the tsvector search does: the
+// words are stemmed and the websearch syntax is accepted.
+func TestSearchRussian(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDb(t)
+	u, err := db.UserCreate(ctx, &users.UserDto{Name: "user", Email: "user@email.com"}, "hash")
+	if err != nil {
+		t.Fatalf("UserCreate() failed: %v", err)
+	}
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "Два вопроса про индексы"}, u.ID)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	if _, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "Индекс ускоряет чтение"}, int(q.ID), u.ID); err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+
+	tests := []struct {
+		text      string
+		wantTotal int64
+	}{
+		{text: "вопрос", wantTotal: 1},
+		{text: "индекс", wantTotal: 2},
+		{text: "индекс -чтение", wantTotal: 1},
+		{text: `"два вопроса"`, wantTotal: 1},
+		{text: "индекс OR", wantTotal: 2},
+	}
+	for _, tt := range tests {
+		page, err := db.Search(ctx, &search.SearchQuery{Text: tt.text, Limit: 10})
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", tt.text, err)
+		}
+		if page.Total != tt.wantTotal {
+			t.Errorf("Search(%q) total: %d, want: %d", tt.text, page.Total, tt.wantTotal)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package gormdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gengeo7/highlitent/types/health"
+	"github.com/pressly/goose/v3"
+)
+
+func (d *Db) Ping(ctx context.Context) error {
+	return d.SqlDb.PingContext(ctx)
+}
+
+// Migrations compares the version of the database with the latest
+// migration in MigrationPath.
+func (d *Db) Migrations(ctx context.Context) (*health.Migrations, error) {
+	current, err := goose.GetDBVersionContext(ctx, d.SqlDb)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest int64
+	ms, err := goose.CollectMigrations(d.MigrationPath, 0, goose.MaxVersion)
+	if err != nil && !errors.Is(err, goose.ErrNoMigrationFiles) {
+		return nil, err
+	}
+	if last, err := ms.Last(); err == nil {
+		latest = last.Version
+	}
+	return &health.Migrations{Current: current, Latest: latest}, nil
+}
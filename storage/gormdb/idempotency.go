@@ -0,0 +1,98 @@
+package gormdb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/idempotency"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (d *Db) IdempotencyStart(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error) {
+	var existing *idempotency.Record
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		// expired keys are dropped lazily by the requests that start
+		err := tx.Where("expires_at < ?", now).
+			Delete(&idempotency.Record{}).Error
+		if err != nil {
+			return err
+		}
+
+		// a concurrent insert of the same key waits for the other
+		// transaction and then inserts nothing
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if res.Error != nil {
+			if strings.Contains(strings.ToLower(res.Error.Error()), "foreign key") {
+				return storage.ErrDbUserNotFound
+			}
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			return nil
+		}
+
+		// the request holding the key in progress is gone, a concurrent
+		// takeover waits for the row lock and then updates nothing
+		res = tx.Model(&idempotency.Record{}).
+			Where("user_id = ? and key = ? and status = 0 and locked_until < ?", record.UserID, record.Key, now).
+			Updates(map[string]any{
+				"fingerprint":  record.Fingerprint,
+				"content_type": "",
+				"body":         nil,
+				"created_at":   now,
+				"expires_at":   record.ExpiresAt,
+				"locked_until": record.LockedUntil,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			return nil
+		}
+
+		var r idempotency.Record
+		err = tx.Where("user_id = ? and key = ?", record.UserID, record.Key).
+			First(&r).Error
+		if err != nil {
+			return err
+		}
+		existing = &r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, storage.ErrDbAlreadyExists
+	}
+	return record, nil
+}
+
+func (d *Db) IdempotencyFinish(ctx context.Context, userID uuid.UUID, key string, status int, contentType string, body []byte) error {
+	res := d.Db.WithContext(ctx).
+		Model(&idempotency.Record{}).
+		Where("user_id = ? and key = ?", userID, key).
+		Updates(map[string]any{
+			"status":       status,
+			"content_type": contentType,
+			"body":         body,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return storage.ErrDbNotFound
+	}
+	return nil
+}
+
+func (d *Db) IdempotencyDelete(ctx context.Context, userID uuid.UUID, key string) error {
+	return d.Db.WithContext(ctx).
+		Where("user_id = ? and key = ?", userID, key).
+		Delete(&idempotency.Record{}).Error
+}
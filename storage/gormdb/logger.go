@@ -0,0 +1,58 @@
+package gormdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gengeo7/highlitent/logger"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is the duration after which a query is logged as
+// a warning instead of a debug record.
+const slowQueryThreshold = 200 * time.Millisecond
+
+// queryLogger sends gorm logs to the context logger, so queries are
+// logged with the attributes of the request that made them.
+type queryLogger struct{}
+
+// NewLogger returns the gorm logger used by Open.
+func NewLogger() gormLogger.Interface {
+	return queryLogger{}
+}
+
+// LogMode is ignored, the level is set by the logger package.
+func (l queryLogger) LogMode(gormLogger.LogLevel) gormLogger.Interface {
+	return l
+}
+
+func (queryLogger) Info(ctx context.Context, msg string, args ...any) {
+	logger.InfoCtx(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (queryLogger) Warn(ctx context.Context, msg string, args ...any) {
+	logger.WarnCtx(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (queryLogger) Error(ctx context.Context, msg string, args ...any) {
+	logger.ErrorCtx(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		logger.ErrorCtx(ctx, "query failed", "sql", sql, "rows", rows, "elapsed_ms", elapsed.Milliseconds(), "error", err.Error())
+	case elapsed > slowQueryThreshold:
+		sql, rows := fc()
+		logger.WarnCtx(ctx, "slow query", "sql", sql, "rows", rows, "elapsed_ms", elapsed.Milliseconds())
+	case logger.FromContext(ctx).Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		logger.DebugCtx(ctx, "query", "sql", sql, "rows", rows, "elapsed_ms", elapsed.Milliseconds())
+	}
+}
@@ -0,0 +1,67 @@
+package gormdb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gengeo7/highlitent/metrics"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// queryMetrics is a gorm plugin timing every query it runs.
+type queryMetrics struct{}
+
+func (queryMetrics) Name() string {
+	return "metrics"
+}
+
+func (queryMetrics) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startQuery),
+		cb.Create().After("gorm:create").Register("metrics:after_create", finishQuery("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startQuery),
+		cb.Query().After("gorm:query").Register("metrics:after_query", finishQuery("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startQuery),
+		cb.Update().After("gorm:update").Register("metrics:after_update", finishQuery("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startQuery),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", finishQuery("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startQuery),
+		cb.Row().After("gorm:row").Register("metrics:after_row", finishQuery("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startQuery),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", finishQuery("raw")),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func finishQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, have := db.InstanceGet(queryStartKey)
+		if !have {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		metrics.ObserveQuery(operation, db.Statement.Table, time.Since(start))
+	}
+}
+
+// Instrument exports the pool stats and the query durations of the
+// storage to metrics and traces its queries. name is the sql dialect,
+// it is called once, after Open.
+func (d *Db) Instrument(name string) error {
+	if err := metrics.RegisterDB(name, d.SqlDb); err != nil {
+		return err
+	}
+	if err := d.Db.Use(queryMetrics{}); err != nil {
+		return err
+	}
+	return d.Db.Use(queryTracing{system: name})
+}
@@ -3,36 +3,71 @@ package gormdb
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gengeo7/highlitent/storage"
 	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/common"
 	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func (d *Db) QuestionsGet(ctx context.Context) ([]questions.Question, error) {
+func (d *Db) QuestionsGet(ctx context.Context, query *questions.QuestionsQuery) (*questions.QuestionsPage, error) {
 	var qs []questions.Question
-	err := d.Db.WithContext(ctx).
+	tx := withTagsFilter(d.Db.WithContext(ctx), query.Tags)
+	err := paginate(tx, query.Cursor, query.Sort, query.Limit).
 		Find(&qs).Error
 	if err != nil {
 		return nil, err
 	}
-	return qs, nil
+
+	page := &questions.QuestionsPage{Questions: qs}
+	if len(qs) > query.Limit {
+		page.Questions = qs[:query.Limit]
+		last := page.Questions[query.Limit-1]
+		page.NextCursor = common.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	ptrs := make([]*questions.Question, len(page.Questions))
+	for i := range page.Questions {
+		ptrs[i] = &page.Questions[i]
+	}
+	if err := loadTags(d.Db.WithContext(ctx), ptrs...); err != nil {
+		return nil, err
+	}
+	return page, nil
 }
 
-func (d *Db) QuestionCreate(ctx context.Context, dto *questions.QuestionDto) (*questions.Question, error) {
+func (d *Db) QuestionCreate(ctx context.Context, dto *questions.QuestionDto, userID uuid.UUID) (*questions.Question, error) {
 	q := &questions.Question{
-		Text: dto.Text,
+		UserID: &userID,
+		Text:   dto.Text,
+		Tags:   slices.Sorted(slices.Values(dto.Tags)),
+	}
+	if q.Tags == nil {
+		q.Tags = []string{}
 	}
 
-	err := d.Db.WithContext(ctx).Create(q).Error
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(q).Error; err != nil {
+			return err
+		}
+		return setTags(tx, q.ID, q.Tags)
+	})
 	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+			return nil, storage.ErrDbUserNotFound
+		}
 		return nil, err
 	}
 	return q, nil
 }
 
-func (d *Db) QuestionGet(ctx context.Context, id int) (*questions.QuestionWithAnswers, error) {
+func (d *Db) QuestionGet(ctx context.Context, id int, query *answers.AnswersQuery) (*questions.QuestionWithAnswers, error) {
 	var result questions.QuestionWithAnswers
 
 	err := d.Db.WithContext(ctx).
@@ -45,22 +80,195 @@ func (d *Db) QuestionGet(ctx context.Context, id int) (*questions.QuestionWithAn
 		}
 		return nil, err
 	}
+	if err := loadTags(d.Db.WithContext(ctx), &result.Question); err != nil {
+		return nil, err
+	}
 
-	var answers []answers.Answer
 	err = d.Db.WithContext(ctx).
+		Model(&answers.Answer{}).
 		Where("question_id = ?", id).
-		Order("created_at asc").
-		Find(&answers).Error
+		Count(&result.AnswersTotal).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// the accepted answer goes first on the first page and takes
+	// one of its places
+	accepted := make([]answers.Answer, 0)
+	acceptedID := result.Question.AcceptedAnswerID
+	if acceptedID != nil && query.Cursor == nil {
+		err = d.Db.WithContext(ctx).
+			Where("id = ?", *acceptedID).
+			Find(&accepted).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	cursor := query.Cursor
+	if cursor != nil && cursor.Accepted {
+		cursor = nil
+	}
+	limit := query.Limit - len(accepted)
 
+	var as []answers.Answer
+	tx := d.Db.WithContext(ctx).Where("question_id = ?", id)
+	if acceptedID != nil {
+		tx = tx.Where("id <> ?", *acceptedID)
+	}
+	if query.Order == answers.OrderScore {
+		tx = paginateByScore(tx, cursor, limit)
+	} else {
+		tx = paginate(tx, cursor, query.Order, limit)
+	}
+	err = tx.Find(&as).Error
 	if err != nil {
 		return nil, err
 	}
 
-	result.Answers = answers
+	result.Answers = append(accepted, as...)
+	if len(as) > limit {
+		result.Answers = result.Answers[:query.Limit]
+		last := result.Answers[query.Limit-1]
+		next := common.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if query.Order == answers.OrderScore {
+			next.Score = &last.Score
+		}
+		next.Accepted = acceptedID != nil && last.ID == *acceptedID
+		result.NextAnswersCursor = next.Encode()
+	}
 	return &result, nil
 }
 
+func (d *Db) QuestionAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	return questionAuthor(d.Db.WithContext(ctx), id)
+}
+
+// QuestionAuthorUnscoped finds questions in the trash as well, so restoring
+// and purging them can be authorized.
+func (d *Db) QuestionAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	return questionAuthor(d.Db.WithContext(ctx).Unscoped(), id)
+}
+
+func questionAuthor(db *gorm.DB, id int) (*uuid.UUID, error) {
+	var q questions.Question
+	err := db.
+		Select("user_id").
+		Where("id = ?", id).
+		Take(&q).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.ErrDbNotFound
+		}
+		return nil, err
+	}
+	return q.UserID, nil
+}
+
+func (d *Db) QuestionUpdate(ctx context.Context, id int, dto *questions.QuestionUpdateDto, editorID uuid.UUID) (*questions.Question, error) {
+	var q questions.Question
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the row is locked so concurrent edits save
+		// the text each of them replaced
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&q).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrDbNotFound
+			}
+			return err
+		}
+		// an edit with the same text saves no revision,
+		// but still bumps updated_at
+		if q.Text != dto.Text {
+			err = tx.Create(&questions.Revision{
+				QuestionID: q.ID,
+				Text:       q.Text,
+				EditorID:   &editorID,
+				Reason:     dto.Reason,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Model(&q).Update("text", dto.Text).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).First(&q).Error
+	})
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "foreign key") {
+			return nil, storage.ErrDbUserNotFound
+		}
+		return nil, err
+	}
+	if err := loadTags(d.Db.WithContext(ctx), &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// QuestionDelete moves the question with its answers to the trash.
+// The answers get the same deleted_at as the question, so restoring
+// the question brings back only them and not the answers deleted
+// before.
 func (d *Db) QuestionDelete(ctx context.Context, id int) error {
+	now := time.Now()
+	return d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&questions.Question{}).
+			Where("id = ?", id).
+			UpdateColumn("deleted_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return storage.ErrDbNotFound
+		}
+
+		return tx.Model(&answers.Answer{}).
+			Where("question_id = ?", id).
+			UpdateColumn("deleted_at", now).Error
+	})
+}
+
+func (d *Db) QuestionRestore(ctx context.Context, id int) (*questions.Question, error) {
+	var q questions.Question
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Where("id = ? and deleted_at is not null", id).
+			First(&q).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return storage.ErrDbNotFound
+			}
+			return err
+		}
+
+		err = tx.Unscoped().Model(&answers.Answer{}).
+			Where("question_id = ? and deleted_at = ?", id, q.DeletedAt.Time).
+			UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&q).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).First(&q).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := loadTags(d.Db.WithContext(ctx), &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// QuestionPurge deletes the question for good, whether it is in the
+// trash or not. Its answers, votes and comments go with it.
+func (d *Db) QuestionPurge(ctx context.Context, id int) error {
 	res := d.Db.WithContext(ctx).Unscoped().
 		Delete(&questions.Question{}, "id = ?", id)
 	if res.Error != nil {
@@ -73,3 +281,49 @@ func (d *Db) QuestionDelete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (d *Db) QuestionAcceptAnswer(ctx context.Context, id int, answerID *int) (*questions.Question, error) {
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if answerID != nil {
+			var count int64
+			err := tx.Model(&answers.Answer{}).
+				Where("id = ? and question_id = ?", *answerID, id).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return storage.ErrDbAnswerNotFound
+			}
+		}
+
+		res := tx.Model(&questions.Question{}).
+			Where("id = ?", id).
+			UpdateColumn("accepted_answer_id", answerID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return storage.ErrDbNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var q questions.Question
+	err = d.Db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&q).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.ErrDbNotFound
+		}
+		return nil, err
+	}
+	if err := loadTags(d.Db.WithContext(ctx), &q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
@@ -0,0 +1,113 @@
+package gormdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/questions"
+	"gorm.io/gorm"
+)
+
+func (d *Db) QuestionRevisionsGet(ctx context.Context, id int) ([]questions.Revision, error) {
+	return d.questionRevisions(ctx, id, false)
+}
+
+// QuestionRevisionsGetUnscoped returns the revisions of questions in the trash as
+// well.
+func (d *Db) QuestionRevisionsGetUnscoped(ctx context.Context, id int) ([]questions.Revision, error) {
+	return d.questionRevisions(ctx, id, true)
+}
+
+func (d *Db) questionRevisions(ctx context.Context, id int, unscoped bool) ([]questions.Revision, error) {
+	tx := d.Db.WithContext(ctx).Model(&questions.Question{})
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+	var count int64
+	err := tx.
+		Where("id = ?", id).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, storage.ErrDbNotFound
+	}
+
+	rs := make([]questions.Revision, 0)
+	err = d.Db.WithContext(ctx).
+		Where("question_id = ?", id).
+		Order("created_at desc, id desc").
+		Find(&rs).Error
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (d *Db) QuestionRevisionGet(ctx context.Context, id int, revisionID int) (*questions.Revision, error) {
+	var r questions.Revision
+	err := d.Db.WithContext(ctx).
+		Where("id = ? and question_id = ?", revisionID, id).
+		First(&r).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.ErrDbRevisionNotFound
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (d *Db) AnswerRevisionsGet(ctx context.Context, id int) ([]answers.Revision, error) {
+	return d.answerRevisions(ctx, id, false)
+}
+
+// AnswerRevisionsGetUnscoped returns the revisions of answers in the trash as
+// well.
+func (d *Db) AnswerRevisionsGetUnscoped(ctx context.Context, id int) ([]answers.Revision, error) {
+	return d.answerRevisions(ctx, id, true)
+}
+
+func (d *Db) answerRevisions(ctx context.Context, id int, unscoped bool) ([]answers.Revision, error) {
+	tx := d.Db.WithContext(ctx).Model(&answers.Answer{})
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+	var count int64
+	err := tx.
+		Where("id = ?", id).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, storage.ErrDbNotFound
+	}
+
+	rs := make([]answers.Revision, 0)
+	err = d.Db.WithContext(ctx).
+		Where("answer_id = ?", id).
+		Order("created_at desc, id desc").
+		Find(&rs).Error
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+func (d *Db) AnswerRevisionGet(ctx context.Context, id int, revisionID int) (*answers.Revision, error) {
+	var r answers.Revision
+	err := d.Db.WithContext(ctx).
+		Where("id = ? and answer_id = ?", revisionID, id).
+		First(&r).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.ErrDbRevisionNotFound
+		}
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,75 @@
+package gormdb
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/search"
+)
+
+// searchConfig is the text search configuration of the queries. The
+// search_vector columns are generated with it in migration 00005, the
+// two have to match or the words are stemmed differently.
+const searchConfig = "russian"
+
+const searchHits = `
+with q as (select websearch_to_tsquery(cast(@config as regconfig), @text) as query)
+select 'question' as type, questions.id, questions.id as question_id, questions.text,
+    questions.created_at, ts_rank(questions.search_vector, q.query) as rank
+from questions, q
+where questions.search_vector @@ q.query and questions.deleted_at is null
+union all
+select 'answer' as type, answers.id, answers.question_id, answers.text,
+    answers.created_at, ts_rank(answers.search_vector, q.query) as rank
+from answers, q
+where answers.search_vector @@ q.query and answers.deleted_at is null`
+
+// the headline is built only for the rows of the requested page,
+// because ts_headline parses the whole text. The found words are
+// marked with storage.SnippetStart and storage.SnippetStop, which are
+// removed from the text first, and the tags are added after escaping.
+const searchPage = `
+select hits.type, hits.id, hits.question_id, hits.created_at, hits.rank,
+    ts_headline(cast(@config as regconfig), translate(hits.text, chr(57344) || chr(57345), ''),
+        websearch_to_tsquery(cast(@config as regconfig), @text),
+        'StartSel=' || chr(57344) || ', StopSel=' || chr(57345) || ', MaxWords=35, MinWords=15, MaxFragments=2') as snippet
+from (` + searchHits + `
+    order by rank desc, created_at desc, id desc
+    limit @limit offset @offset
+) hits
+order by hits.rank desc, hits.created_at desc, hits.id desc`
+
+const searchCount = `select count(*) from (` + searchHits + `) hits`
+
+func (d *Db) Search(ctx context.Context, query *search.SearchQuery) (*search.SearchPage, error) {
+	args := map[string]any{
+		"config": searchConfig,
+		"text":   query.Text,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	}
+
+	var page search.SearchPage
+	err := d.Db.WithContext(ctx).
+		Raw(searchCount, args).
+		Scan(&page.Total).Error
+	if err != nil {
+		return nil, err
+	}
+
+	page.Results = make([]search.Result, 0)
+	if page.Total == 0 {
+		return &page, nil
+	}
+
+	err = d.Db.WithContext(ctx).
+		Raw(searchPage, args).
+		Scan(&page.Results).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Results {
+		page.Results[i].Snippet = storage.EscapeSnippet(page.Results[i].Snippet)
+	}
+	return &page, nil
+}
@@ -0,0 +1,113 @@
+package gormdb
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/tags"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (d *Db) TagsGet(ctx context.Context, query *tags.TagsQuery) ([]tags.TagWithCount, error) {
+	ts := make([]tags.TagWithCount, 0)
+	err := d.Db.WithContext(ctx).
+		Table("tags").
+		Select("tags.name, count(*) as questions").
+		Joins("join question_tags on question_tags.tag_id = tags.id").
+		Joins("join questions on questions.id = question_tags.question_id and questions.deleted_at is null").
+		Group("tags.id, tags.name").
+		Order("questions desc, tags.name asc").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(&ts).Error
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// setTags marks the question by names, creating the missing tags.
+func setTags(tx *gorm.DB, questionID uint, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	created := make([]tags.Tag, len(names))
+	for i, name := range names {
+		created[i] = tags.Tag{Name: name}
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&created).Error
+	if err != nil {
+		return err
+	}
+
+	// ids of the tags that already existed are not returned
+	// on conflict, so all of them are selected again
+	var ts []tags.Tag
+	err = tx.Where("name in ?", names).Find(&ts).Error
+	if err != nil {
+		return err
+	}
+
+	links := make([]tags.QuestionTag, len(ts))
+	for i, t := range ts {
+		links[i] = tags.QuestionTag{QuestionID: questionID, TagID: t.ID}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+// loadTags fills Tags of the questions with a single query.
+func loadTags(tx *gorm.DB, qs ...*questions.Question) error {
+	if len(qs) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(qs))
+	for i, q := range qs {
+		ids[i] = q.ID
+	}
+
+	var rows []struct {
+		QuestionID uint
+		Name       string
+	}
+	err := tx.Table("question_tags").
+		Select("question_tags.question_id, tags.name").
+		Joins("join tags on tags.id = question_tags.tag_id").
+		Where("question_tags.question_id in ?", ids).
+		Order("tags.name asc").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byQuestion := make(map[uint][]string, len(qs))
+	for _, row := range rows {
+		byQuestion[row.QuestionID] = append(byQuestion[row.QuestionID], row.Name)
+	}
+	for _, q := range qs {
+		q.Tags = byQuestion[q.ID]
+		if q.Tags == nil {
+			q.Tags = []string{}
+		}
+	}
+	return nil
+}
+
+// withTagsFilter keeps questions marked by all of names.
+func withTagsFilter(tx *gorm.DB, names []string) *gorm.DB {
+	if len(names) == 0 {
+		return tx
+	}
+	marked := tx.Session(&gorm.Session{NewDB: true}).
+		Table("question_tags").
+		Select("question_tags.question_id").
+		Joins("join tags on tags.id = question_tags.tag_id").
+		Where("tags.name in ?", names).
+		Group("question_tags.question_id").
+		Having("count(*) = ?", len(names))
+	return tx.Where("id in (?)", marked)
+}
@@ -0,0 +1,88 @@
+package gormdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (d *Db) UserCreate(ctx context.Context, dto *users.UserDto, passwordHash string) (*users.User, error) {
+	u := &users.User{
+		ID:           uuid.New(),
+		Name:         dto.Name,
+		Email:        dto.Email,
+		Role:         users.RoleUser,
+		PasswordHash: passwordHash,
+	}
+
+	err := d.Db.WithContext(ctx).Create(u).Error
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "unique constraint") {
+			return nil, storage.ErrDbAlreadyExists
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func (d *Db) UserGet(ctx context.Context, id uuid.UUID) (*users.User, error) {
+	var u users.User
+	err := d.Db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&u).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.ErrDbNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (d *Db) UserGetByEmail(ctx context.Context, email string) (*users.User, error) {
+	var u users.User
+	err := d.Db.WithContext(ctx).
+		Where("email = ?", email).
+		First(&u).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, storage.ErrDbNotFound
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (d *Db) UsersGet(ctx context.Context, query *users.UsersQuery) ([]users.User, error) {
+	var us []users.User
+	err := d.Db.WithContext(ctx).
+		Order("created_at asc, id asc").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&us).Error
+	if err != nil {
+		return nil, err
+	}
+	return us, nil
+}
+
+func (d *Db) UserSetRole(ctx context.Context, id uuid.UUID, role users.Role) (*users.User, error) {
+	res := d.Db.WithContext(ctx).
+		Model(&users.User{}).
+		Where("id = ?", id).
+		Update("role", role)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, storage.ErrDbNotFound
+	}
+	return d.UserGet(ctx, id)
+}
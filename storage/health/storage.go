@@ -0,0 +1,14 @@
+package health
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/health"
+)
+
+// Storage reports the state of the storage. Migrations returns nil for
+// storages without migrations.
+type Storage interface {
+	Ping(ctx context.Context) error
+	Migrations(ctx context.Context) (*health.Migrations, error)
+}
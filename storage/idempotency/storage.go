@@ -0,0 +1,19 @@
+package idempotency
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/idempotency"
+	"github.com/google/uuid"
+)
+
+// Storage keeps the responses of requests sent with an Idempotency-Key.
+// IdempotencyStart saves the record unless a live one with the same key
+// exists, then it returns that one with storage.ErrDbAlreadyExists. A
+// record in progress past its LockedUntil is replaced, the request that
+// reserved it is taken as lost.
+type Storage interface {
+	IdempotencyStart(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error)
+	IdempotencyFinish(ctx context.Context, userID uuid.UUID, key string, status int, contentType string, body []byte) error
+	IdempotencyDelete(ctx context.Context, userID uuid.UUID, key string) error
+}
@@ -0,0 +1,208 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (d *Db) AnswerGet(ctx context.Context, id int) (*answers.Answer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	a, have := d.answers[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	return &a, nil
+}
+
+func (d *Db) AnswerCreate(ctx context.Context, dto *answers.AnswerDto, questionID int, userID uuid.UUID) (*answers.Answer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, have := d.users[userID]; !have {
+		return nil, storage.ErrDbUserNotFound
+	}
+	if _, have := d.questions[uint(questionID)]; !have {
+		return nil, storage.ErrDbNotFound
+	}
+
+	now := time.Now()
+	d.lastAnswer++
+	a := answers.Answer{
+		ID:         d.lastAnswer,
+		QuestionID: questionID,
+		UserID:     userID,
+		Text:       dto.Text,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	d.answers[a.ID] = a
+	return &a, nil
+}
+
+func (d *Db) AnswerAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	a, have := d.answers[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	return &a.UserID, nil
+}
+
+// AnswerAuthorUnscoped finds answers in the trash as well, so restoring
+// and purging them can be authorized.
+func (d *Db) AnswerAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	a, have := d.answers[uint(id)]
+	if !have {
+		a, have = d.trashedAnswers[uint(id)]
+	}
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	return &a.UserID, nil
+}
+
+func (d *Db) AnswerUpdate(ctx context.Context, id int, dto *answers.AnswerUpdateDto, editorID uuid.UUID) (*answers.Answer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, have := d.answers[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	// an edit with the same text saves no revision,
+	// but still bumps UpdatedAt
+	now := time.Now()
+	if a.Text != dto.Text {
+		if _, have := d.users[editorID]; !have {
+			return nil, storage.ErrDbUserNotFound
+		}
+		d.lastAnswerRevision++
+		d.answerRevisions[a.ID] = append(d.answerRevisions[a.ID], answers.Revision{
+			ID:        d.lastAnswerRevision,
+			AnswerID:  a.ID,
+			Text:      a.Text,
+			EditorID:  &editorID,
+			Reason:    dto.Reason,
+			CreatedAt: now,
+		})
+	}
+	a.Text = dto.Text
+	a.UpdatedAt = now
+	d.answers[a.ID] = a
+	return &a, nil
+}
+
+func (d *Db) AnswerDelete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, have := d.answers[uint(id)]
+	if !have {
+		return storage.ErrDbNotFound
+	}
+	d.unacceptAnswer(a)
+	a.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	delete(d.answers, a.ID)
+	d.trashedAnswers[a.ID] = a
+	return nil
+}
+
+// AnswerRestore takes the answer out of the trash, an answer whose
+// question is in the trash is restored only with the question.
+func (d *Db) AnswerRestore(ctx context.Context, id int) (*answers.Answer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, have := d.trashedAnswers[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	if _, have := d.questions[uint(a.QuestionID)]; !have {
+		return nil, storage.ErrDbNotFound
+	}
+	a.DeletedAt = gorm.DeletedAt{}
+	delete(d.trashedAnswers, a.ID)
+	d.answers[a.ID] = a
+	return &a, nil
+}
+
+func (d *Db) AnswerPurge(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, have := d.answers[uint(id)]
+	if !have {
+		a, have = d.trashedAnswers[uint(id)]
+	}
+	if !have {
+		return storage.ErrDbNotFound
+	}
+	d.unacceptAnswer(a)
+	d.deleteAnswer(a.ID)
+	return nil
+}
+
+func (d *Db) AnswerVote(ctx context.Context, id int, userID uuid.UUID, value int) (*answers.Answer, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	a, have := d.answers[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	if _, have := d.users[userID]; !have {
+		return nil, storage.ErrDbUserNotFound
+	}
+
+	now := time.Now()
+	key := voteKey{answerID: a.ID, userID: userID}
+	v, have := d.votes[key]
+	if !have {
+		v = answers.Vote{AnswerID: a.ID, UserID: userID, CreatedAt: now}
+	}
+	a.Score += value - v.Value
+	v.Value = value
+	v.UpdatedAt = now
+	d.votes[key] = v
+	d.answers[a.ID] = a
+	return &a, nil
+}
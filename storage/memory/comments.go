@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/gengeo7/highlitent/types/common"
+	"github.com/google/uuid"
+)
+
+func (d *Db) CommentCreate(ctx context.Context, dto *comments.CommentDto, answerID int, userID uuid.UUID) (*comments.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, have := d.answers[uint(answerID)]; !have {
+		return nil, storage.ErrDbNotFound
+	}
+	if dto.ParentID != nil {
+		parent, have := d.comments[*dto.ParentID]
+		if !have || parent.AnswerID != answerID {
+			return nil, storage.ErrDbCommentNotFound
+		}
+	}
+	if _, have := d.users[userID]; !have {
+		return nil, storage.ErrDbUserNotFound
+	}
+
+	now := time.Now()
+	d.lastComment++
+	c := comments.Comment{
+		ID:        d.lastComment,
+		AnswerID:  answerID,
+		ParentID:  dto.ParentID,
+		UserID:    userID,
+		Text:      dto.Text,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	d.comments[c.ID] = c
+	return &c, nil
+}
+
+func (d *Db) CommentsGet(ctx context.Context, answerID int) ([]comments.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if _, have := d.answers[uint(answerID)]; !have {
+		return nil, storage.ErrDbNotFound
+	}
+
+	cs := make([]comments.Comment, 0)
+	for _, c := range d.comments {
+		if c.AnswerID == answerID {
+			cs = append(cs, c)
+		}
+	}
+	slices.SortFunc(cs, func(a, b comments.Comment) int {
+		return compareKeys(a.CreatedAt, a.ID, &common.Cursor{CreatedAt: b.CreatedAt, ID: b.ID})
+	})
+	return cs, nil
+}
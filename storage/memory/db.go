@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"cmp"
+	"sync"
+	"time"
+
+	answersStorage "github.com/gengeo7/highlitent/storage/answers"
+	commentsStorage "github.com/gengeo7/highlitent/storage/comments"
+	healthStorage "github.com/gengeo7/highlitent/storage/health"
+	idempotencyStorage "github.com/gengeo7/highlitent/storage/idempotency"
+	questionsStorage "github.com/gengeo7/highlitent/storage/questions"
+	searchStorage "github.com/gengeo7/highlitent/storage/search"
+	tagsStorage "github.com/gengeo7/highlitent/storage/tags"
+	usersStorage "github.com/gengeo7/highlitent/storage/users"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/gengeo7/highlitent/types/common"
+	"github.com/gengeo7/highlitent/types/idempotency"
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+)
+
+var (
+	_ questionsStorage.Storage   = (*Db)(nil)
+	_ answersStorage.Storage     = (*Db)(nil)
+	_ searchStorage.Storage      = (*Db)(nil)
+	_ usersStorage.Storage       = (*Db)(nil)
+	_ tagsStorage.Storage        = (*Db)(nil)
+	_ commentsStorage.Storage    = (*Db)(nil)
+	_ healthStorage.Storage      = (*Db)(nil)
+	_ idempotencyStorage.Storage = (*Db)(nil)
+)
+
+type Db struct {
+	mu           sync.RWMutex
+	questions    map[uint]questions.Question
+	answers      map[uint]answers.Answer
+	users        map[uuid.UUID]users.User
+	votes        map[voteKey]answers.Vote
+	comments     map[uint]comments.Comment
+	lastQuestion uint
+	lastAnswer   uint
+	lastComment  uint
+
+	// questions and answers in the trash are moved out of the maps
+	// above, so the reads don't see them
+	trashedQuestions map[uint]questions.Question
+	trashedAnswers   map[uint]answers.Answer
+
+	// revisions are kept per question and answer in the order
+	// they were made
+	questionRevisions    map[uint][]questions.Revision
+	answerRevisions      map[uint][]answers.Revision
+	lastQuestionRevision uint
+	lastAnswerRevision   uint
+
+	// responses of requests sent with an Idempotency-Key
+	idempotency map[idempotencyKey]idempotency.Record
+}
+
+type voteKey struct {
+	answerID uint
+	userID   uuid.UUID
+}
+
+// unacceptAnswer stops the answer from being accepted by its question,
+// d.mu must be held.
+func (d *Db) unacceptAnswer(a answers.Answer) {
+	for _, qs := range []map[uint]questions.Question{d.questions, d.trashedQuestions} {
+		q, have := qs[uint(a.QuestionID)]
+		if have && q.AcceptedAnswerID != nil && *q.AcceptedAnswerID == a.ID {
+			q.AcceptedAnswerID = nil
+			qs[q.ID] = q
+		}
+	}
+}
+
+// deleteAnswer removes the answer for good with its votes, comments
+// and revisions, d.mu must be held.
+func (d *Db) deleteAnswer(id uint) {
+	delete(d.answers, id)
+	delete(d.trashedAnswers, id)
+	delete(d.answerRevisions, id)
+	for k := range d.votes {
+		if k.answerID == id {
+			delete(d.votes, k)
+		}
+	}
+	for commentID, c := range d.comments {
+		if c.AnswerID == int(id) {
+			delete(d.comments, commentID)
+		}
+	}
+}
+
+// compareKeys orders rows by (created_at, id) the same way
+// keyset pagination in the sql storages does.
+func compareKeys(createdAt time.Time, id uint, c *common.Cursor) int {
+	if res := createdAt.Compare(c.CreatedAt); res != 0 {
+		return res
+	}
+	return cmp.Compare(id, c.ID)
+}
+
+// compareScoreKeys orders answers by score desc and then by
+// (created_at, id) asc, the order of answers.OrderScore.
+func compareScoreKeys(a answers.Answer, c *common.Cursor) int {
+	score := 0
+	if c.Score != nil {
+		score = *c.Score
+	}
+	if res := cmp.Compare(score, a.Score); res != 0 {
+		return res
+	}
+	return compareKeys(a.CreatedAt, a.ID, c)
+}
+
+func NewDb() *Db {
+	return &Db{
+		questions: make(map[uint]questions.Question),
+		answers:   make(map[uint]answers.Answer),
+		users:     make(map[uuid.UUID]users.User),
+		votes:     make(map[voteKey]answers.Vote),
+		comments:  make(map[uint]comments.Comment),
+
+		trashedQuestions: make(map[uint]questions.Question),
+		trashedAnswers:   make(map[uint]answers.Answer),
+
+		questionRevisions: make(map[uint][]questions.Revision),
+		answerRevisions:   make(map[uint][]answers.Revision),
+
+		idempotency: make(map[idempotencyKey]idempotency.Record),
+	}
+}
+
+// Close is a no-op, the data lives as long as the Db.
+func (d *Db) Close() error {
+	return nil
+}
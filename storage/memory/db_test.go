@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/gengeo7/highlitent/storage/storagetest"
+)
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return NewDb()
+	})
+}
@@ -0,0 +1,16 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/health"
+)
+
+func (d *Db) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Migrations returns nil, the memory storage has no schema.
+func (d *Db) Migrations(ctx context.Context) (*health.Migrations, error) {
+	return nil, ctx.Err()
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/idempotency"
+	"github.com/google/uuid"
+)
+
+type idempotencyKey struct {
+	userID uuid.UUID
+	key    string
+}
+
+func (d *Db) IdempotencyStart(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, have := d.users[record.UserID]; !have {
+		return nil, storage.ErrDbUserNotFound
+	}
+	now := time.Now()
+	for k, r := range d.idempotency {
+		if r.ExpiresAt.Before(now) {
+			delete(d.idempotency, k)
+		}
+	}
+
+	k := idempotencyKey{userID: record.UserID, key: record.Key}
+	// the request holding the key in progress is gone when its lock
+	// is over, the key is taken over
+	if existing, have := d.idempotency[k]; have && (existing.Status != 0 || !existing.LockedUntil.Before(now)) {
+		return &existing, storage.ErrDbAlreadyExists
+	}
+	r := *record
+	r.CreatedAt = now
+	d.idempotency[k] = r
+	return &r, nil
+}
+
+func (d *Db) IdempotencyFinish(ctx context.Context, userID uuid.UUID, key string, status int, contentType string, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	k := idempotencyKey{userID: userID, key: key}
+	r, have := d.idempotency[k]
+	if !have {
+		return storage.ErrDbNotFound
+	}
+	r.Status = status
+	r.ContentType = contentType
+	r.Body = body
+	d.idempotency[k] = r
+	return nil
+}
+
+func (d *Db) IdempotencyDelete(ctx context.Context, userID uuid.UUID, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.idempotency, idempotencyKey{userID: userID, key: key})
+	return nil
+}
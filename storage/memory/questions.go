@@ -0,0 +1,317 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/common"
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (d *Db) QuestionsGet(ctx context.Context, query *questions.QuestionsQuery) (*questions.QuestionsPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	sign := 1
+	if query.Sort != common.OrderAsc {
+		sign = -1
+	}
+
+	qs := make([]questions.Question, 0)
+	for _, q := range d.questions {
+		if query.Cursor != nil && sign*compareKeys(q.CreatedAt, q.ID, query.Cursor) <= 0 {
+			continue
+		}
+		if !hasTags(q, query.Tags) {
+			continue
+		}
+		qs = append(qs, q)
+	}
+	slices.SortFunc(qs, func(a, b questions.Question) int {
+		return sign * compareKeys(a.CreatedAt, a.ID, &common.Cursor{CreatedAt: b.CreatedAt, ID: b.ID})
+	})
+
+	page := &questions.QuestionsPage{Questions: qs}
+	if len(qs) > query.Limit {
+		page.Questions = qs[:query.Limit]
+		last := page.Questions[query.Limit-1]
+		page.NextCursor = common.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+func (d *Db) QuestionCreate(ctx context.Context, dto *questions.QuestionDto, userID uuid.UUID) (*questions.Question, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, have := d.users[userID]; !have {
+		return nil, storage.ErrDbUserNotFound
+	}
+
+	now := time.Now()
+	d.lastQuestion++
+	q := questions.Question{
+		ID:        d.lastQuestion,
+		UserID:    &userID,
+		Text:      dto.Text,
+		Tags:      slices.Sorted(slices.Values(dto.Tags)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if q.Tags == nil {
+		q.Tags = []string{}
+	}
+	d.questions[q.ID] = q
+	return &q, nil
+}
+
+func (d *Db) QuestionGet(ctx context.Context, id int, query *answers.AnswersQuery) (*questions.QuestionWithAnswers, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	q, have := d.questions[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+
+	sign := 1
+	if query.Order == common.OrderDesc {
+		sign = -1
+	}
+	compare := func(a answers.Answer, c *common.Cursor) int {
+		return sign * compareKeys(a.CreatedAt, a.ID, c)
+	}
+	if query.Order == answers.OrderScore {
+		compare = compareScoreKeys
+	}
+
+	// the accepted answer goes first on the first page and takes
+	// one of its places
+	accepted := make([]answers.Answer, 0)
+	if q.AcceptedAnswerID != nil && query.Cursor == nil {
+		if a, have := d.answers[*q.AcceptedAnswerID]; have {
+			accepted = append(accepted, a)
+		}
+	}
+	cursor := query.Cursor
+	if cursor != nil && cursor.Accepted {
+		cursor = nil
+	}
+	limit := query.Limit - len(accepted)
+
+	result := &questions.QuestionWithAnswers{Question: q}
+	as := make([]answers.Answer, 0)
+	for _, a := range d.answers {
+		if a.QuestionID != id {
+			continue
+		}
+		result.AnswersTotal++
+		if q.AcceptedAnswerID != nil && a.ID == *q.AcceptedAnswerID {
+			continue
+		}
+		if cursor != nil && compare(a, cursor) <= 0 {
+			continue
+		}
+		as = append(as, a)
+	}
+	slices.SortFunc(as, func(a, b answers.Answer) int {
+		return compare(a, &common.Cursor{Score: &b.Score, CreatedAt: b.CreatedAt, ID: b.ID})
+	})
+
+	result.Answers = append(accepted, as...)
+	if len(as) > limit {
+		result.Answers = result.Answers[:query.Limit]
+		last := result.Answers[query.Limit-1]
+		next := common.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if query.Order == answers.OrderScore {
+			next.Score = &last.Score
+		}
+		next.Accepted = q.AcceptedAnswerID != nil && last.ID == *q.AcceptedAnswerID
+		result.NextAnswersCursor = next.Encode()
+	}
+	return result, nil
+}
+
+func (d *Db) QuestionAuthor(ctx context.Context, id int) (*uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	q, have := d.questions[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	return q.UserID, nil
+}
+
+// QuestionAuthorUnscoped finds questions in the trash as well, so
+// restoring and purging them can be authorized.
+func (d *Db) QuestionAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	q, have := d.questions[uint(id)]
+	if !have {
+		q, have = d.trashedQuestions[uint(id)]
+	}
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	return q.UserID, nil
+}
+
+func (d *Db) QuestionUpdate(ctx context.Context, id int, dto *questions.QuestionUpdateDto, editorID uuid.UUID) (*questions.Question, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	q, have := d.questions[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	// an edit with the same text saves no revision,
+	// but still bumps UpdatedAt
+	now := time.Now()
+	if q.Text != dto.Text {
+		if _, have := d.users[editorID]; !have {
+			return nil, storage.ErrDbUserNotFound
+		}
+		d.lastQuestionRevision++
+		d.questionRevisions[q.ID] = append(d.questionRevisions[q.ID], questions.Revision{
+			ID:         d.lastQuestionRevision,
+			QuestionID: q.ID,
+			Text:       q.Text,
+			EditorID:   &editorID,
+			Reason:     dto.Reason,
+			CreatedAt:  now,
+		})
+	}
+	q.Text = dto.Text
+	q.UpdatedAt = now
+	d.questions[q.ID] = q
+	return &q, nil
+}
+
+// QuestionDelete moves the question with its answers to the trash,
+// the answers get the same DeletedAt as the question.
+func (d *Db) QuestionDelete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	q, have := d.questions[uint(id)]
+	if !have {
+		return storage.ErrDbNotFound
+	}
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+	q.DeletedAt = deletedAt
+	delete(d.questions, q.ID)
+	d.trashedQuestions[q.ID] = q
+
+	for answerID, a := range d.answers {
+		if a.QuestionID == id {
+			a.DeletedAt = deletedAt
+			delete(d.answers, answerID)
+			d.trashedAnswers[answerID] = a
+		}
+	}
+	return nil
+}
+
+func (d *Db) QuestionRestore(ctx context.Context, id int) (*questions.Question, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	q, have := d.trashedQuestions[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	for answerID, a := range d.trashedAnswers {
+		if a.QuestionID == id && a.DeletedAt.Time.Equal(q.DeletedAt.Time) {
+			a.DeletedAt = gorm.DeletedAt{}
+			delete(d.trashedAnswers, answerID)
+			d.answers[answerID] = a
+		}
+	}
+	q.DeletedAt = gorm.DeletedAt{}
+	delete(d.trashedQuestions, q.ID)
+	d.questions[q.ID] = q
+	return &q, nil
+}
+
+func (d *Db) QuestionPurge(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, live := d.questions[uint(id)]
+	_, trashed := d.trashedQuestions[uint(id)]
+	if !live && !trashed {
+		return storage.ErrDbNotFound
+	}
+	delete(d.questions, uint(id))
+	delete(d.trashedQuestions, uint(id))
+	delete(d.questionRevisions, uint(id))
+
+	for _, as := range []map[uint]answers.Answer{d.answers, d.trashedAnswers} {
+		for answerID, a := range as {
+			if a.QuestionID == id {
+				d.deleteAnswer(answerID)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Db) QuestionAcceptAnswer(ctx context.Context, id int, answerID *int) (*questions.Question, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var acceptedID *uint
+	if answerID != nil {
+		a, have := d.answers[uint(*answerID)]
+		if !have || a.QuestionID != id {
+			return nil, storage.ErrDbAnswerNotFound
+		}
+		acceptedID = &a.ID
+	}
+
+	q, have := d.questions[uint(id)]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	q.AcceptedAnswerID = acceptedID
+	d.questions[q.ID] = q
+	return &q, nil
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/questions"
+)
+
+func (d *Db) QuestionRevisionsGet(ctx context.Context, id int) ([]questions.Revision, error) {
+	return d.getQuestionRevisions(ctx, id, false)
+}
+
+// QuestionRevisionsGetUnscoped returns the revisions of questions in the trash as
+// well.
+func (d *Db) QuestionRevisionsGetUnscoped(ctx context.Context, id int) ([]questions.Revision, error) {
+	return d.getQuestionRevisions(ctx, id, true)
+}
+
+func (d *Db) getQuestionRevisions(ctx context.Context, id int, unscoped bool) ([]questions.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, have := d.questions[uint(id)]
+	if !have && unscoped {
+		_, have = d.trashedQuestions[uint(id)]
+	}
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	rs := slices.Clone(d.questionRevisions[uint(id)])
+	slices.Reverse(rs)
+	if rs == nil {
+		rs = make([]questions.Revision, 0)
+	}
+	return rs, nil
+}
+
+func (d *Db) QuestionRevisionGet(ctx context.Context, id int, revisionID int) (*questions.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, r := range d.questionRevisions[uint(id)] {
+		if r.ID == uint(revisionID) {
+			return &r, nil
+		}
+	}
+	return nil, storage.ErrDbRevisionNotFound
+}
+
+func (d *Db) AnswerRevisionsGet(ctx context.Context, id int) ([]answers.Revision, error) {
+	return d.getAnswerRevisions(ctx, id, false)
+}
+
+// AnswerRevisionsGetUnscoped returns the revisions of answers in the trash as
+// well.
+func (d *Db) AnswerRevisionsGetUnscoped(ctx context.Context, id int) ([]answers.Revision, error) {
+	return d.getAnswerRevisions(ctx, id, true)
+}
+
+func (d *Db) getAnswerRevisions(ctx context.Context, id int, unscoped bool) ([]answers.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, have := d.answers[uint(id)]
+	if !have && unscoped {
+		_, have = d.trashedAnswers[uint(id)]
+	}
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	rs := slices.Clone(d.answerRevisions[uint(id)])
+	slices.Reverse(rs)
+	if rs == nil {
+		rs = make([]answers.Revision, 0)
+	}
+	return rs, nil
+}
+
+func (d *Db) AnswerRevisionGet(ctx context.Context, id int, revisionID int) (*answers.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, r := range d.answerRevisions[uint(id)] {
+		if r.ID == uint(revisionID) {
+			return &r, nil
+		}
+	}
+	return nil, storage.ErrDbRevisionNotFound
+}
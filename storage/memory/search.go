@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"html"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gengeo7/highlitent/types/search"
+)
+
+const snippetWords = 32
+
+type token struct {
+	start int
+	end   int
+	word  string
+}
+
+func tokenize(text string) []token {
+	tokens := make([]token, 0)
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			tokens = append(tokens, token{start: start, end: i, word: strings.ToLower(text[start:i])})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{start: start, end: len(text), word: strings.ToLower(text[start:])})
+	}
+	return tokens
+}
+
+// match returns how many words of the text are search terms,
+// or zero when some of the terms are missing.
+func match(tokens []token, terms map[string]bool) int {
+	found := make(map[string]bool, len(terms))
+	hits := 0
+	for _, t := range tokens {
+		if terms[t.word] {
+			found[t.word] = true
+			hits++
+		}
+	}
+	if len(found) != len(terms) {
+		return 0
+	}
+	return hits
+}
+
+// snippet is the text around the first found word, the text is
+// HTML-escaped and the found words are put in <mark> tags.
+func snippet(text string, tokens []token, terms map[string]bool) string {
+	first := slices.IndexFunc(tokens, func(t token) bool { return terms[t.word] })
+	lo := max(first-snippetWords/4, 0)
+	hi := min(lo+snippetWords, len(tokens))
+
+	var b strings.Builder
+	if lo > 0 {
+		b.WriteString("…")
+	}
+	pos := tokens[lo].start
+	for _, t := range tokens[lo:hi] {
+		b.WriteString(html.EscapeString(text[pos:t.start]))
+		if terms[t.word] {
+			b.WriteString("<mark>" + html.EscapeString(text[t.start:t.end]) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(text[t.start:t.end]))
+		}
+		pos = t.end
+	}
+	if hi < len(tokens) {
+		b.WriteString("…")
+	} else {
+		b.WriteString(html.EscapeString(text[pos:]))
+	}
+	return b.String()
+}
+
+func (d *Db) Search(ctx context.Context, query *search.SearchQuery) (*search.SearchPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	terms := make(map[string]bool)
+	for _, t := range tokenize(query.Text) {
+		terms[t.word] = true
+	}
+
+	results := make([]search.Result, 0)
+	add := func(kind search.Kind, id, questionID uint, text string, createdAt time.Time) {
+		tokens := tokenize(text)
+		hits := match(tokens, terms)
+		if hits == 0 {
+			return
+		}
+		results = append(results, search.Result{
+			Type:       kind,
+			ID:         id,
+			QuestionID: questionID,
+			Snippet:    snippet(text, tokens, terms),
+			Rank:       float64(hits) / float64(len(tokens)),
+			CreatedAt:  createdAt,
+		})
+	}
+	if len(terms) > 0 {
+		for _, q := range d.questions {
+			add(search.KindQuestion, q.ID, q.ID, q.Text, q.CreatedAt)
+		}
+		for _, a := range d.answers {
+			add(search.KindAnswer, a.ID, uint(a.QuestionID), a.Text, a.CreatedAt)
+		}
+	}
+
+	slices.SortFunc(results, func(a, b search.Result) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	page := &search.SearchPage{Total: int64(len(results))}
+	lo := min(query.Offset, len(results))
+	hi := min(lo+query.Limit, len(results))
+	page.Results = results[lo:hi]
+	return page, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/tags"
+)
+
+func (d *Db) TagsGet(ctx context.Context, query *tags.TagsQuery) ([]tags.TagWithCount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, q := range d.questions {
+		for _, name := range q.Tags {
+			counts[name]++
+		}
+	}
+
+	ts := make([]tags.TagWithCount, 0, len(counts))
+	for name, count := range counts {
+		ts = append(ts, tags.TagWithCount{Name: name, Questions: count})
+	}
+	slices.SortFunc(ts, func(a, b tags.TagWithCount) int {
+		if c := cmp.Compare(b.Questions, a.Questions); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	lo := min(query.Offset, len(ts))
+	hi := min(lo+query.Limit, len(ts))
+	return ts[lo:hi], nil
+}
+
+// hasTags reports whether q is marked by all of names.
+func hasTags(q questions.Question, names []string) bool {
+	for _, name := range names {
+		if !slices.Contains(q.Tags, name) {
+			return false
+		}
+	}
+	return true
+}
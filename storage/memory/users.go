@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+)
+
+func (d *Db) UserCreate(ctx context.Context, dto *users.UserDto, passwordHash string) (*users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, u := range d.users {
+		if u.Email == dto.Email {
+			return nil, storage.ErrDbAlreadyExists
+		}
+	}
+
+	now := time.Now()
+	u := users.User{
+		ID:           uuid.New(),
+		Name:         dto.Name,
+		Email:        dto.Email,
+		Role:         users.RoleUser,
+		PasswordHash: passwordHash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	d.users[u.ID] = u
+	return &u, nil
+}
+
+func (d *Db) UserGet(ctx context.Context, id uuid.UUID) (*users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	u, have := d.users[id]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	return &u, nil
+}
+
+func (d *Db) UserGetByEmail(ctx context.Context, email string) (*users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, u := range d.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, storage.ErrDbNotFound
+}
+
+func (d *Db) UsersGet(ctx context.Context, query *users.UsersQuery) ([]users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	us := make([]users.User, 0, len(d.users))
+	for _, u := range d.users {
+		us = append(us, u)
+	}
+	slices.SortFunc(us, func(a, b users.User) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID.String(), b.ID.String())
+	})
+
+	lo := min(query.Offset, len(us))
+	hi := min(lo+query.Limit, len(us))
+	return us[lo:hi], nil
+}
+
+func (d *Db) UserSetRole(ctx context.Context, id uuid.UUID, role users.Role) (*users.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	u, have := d.users[id]
+	if !have {
+		return nil, storage.ErrDbNotFound
+	}
+	u.Role = role
+	u.UpdatedAt = time.Now()
+	d.users[id] = u
+	return &u, nil
+}
@@ -3,12 +3,23 @@ package questions
 import (
 	"context"
 
+	"github.com/gengeo7/highlitent/types/answers"
 	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/google/uuid"
 )
 
 type Storage interface {
-	QuestionsGet(ctx context.Context) ([]questions.Question, error)
-	QuestionCreate(ctx context.Context, dto *questions.QuestionDto) (*questions.Question, error)
-	QuestionGet(ctx context.Context, id int) (*questions.QuestionWithAnswers, error)
+	QuestionsGet(ctx context.Context, query *questions.QuestionsQuery) (*questions.QuestionsPage, error)
+	QuestionCreate(ctx context.Context, dto *questions.QuestionDto, userID uuid.UUID) (*questions.Question, error)
+	QuestionGet(ctx context.Context, id int, query *answers.AnswersQuery) (*questions.QuestionWithAnswers, error)
+	QuestionAuthor(ctx context.Context, id int) (*uuid.UUID, error)
+	QuestionAuthorUnscoped(ctx context.Context, id int) (*uuid.UUID, error)
+	QuestionUpdate(ctx context.Context, id int, dto *questions.QuestionUpdateDto, editorID uuid.UUID) (*questions.Question, error)
+	QuestionRevisionsGet(ctx context.Context, id int) ([]questions.Revision, error)
+	QuestionRevisionsGetUnscoped(ctx context.Context, id int) ([]questions.Revision, error)
+	QuestionRevisionGet(ctx context.Context, id int, revisionID int) (*questions.Revision, error)
 	QuestionDelete(ctx context.Context, id int) error
+	QuestionRestore(ctx context.Context, id int) (*questions.Question, error)
+	QuestionPurge(ctx context.Context, id int) error
+	QuestionAcceptAnswer(ctx context.Context, id int, answerID *int) (*questions.Question, error)
 }
@@ -0,0 +1,11 @@
+package search
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/search"
+)
+
+type Storage interface {
+	Search(ctx context.Context, query *search.SearchQuery) (*search.SearchPage, error)
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"html"
+	"strings"
+)
+
+// SnippetStart and SnippetStop are put around the found words by the
+// database instead of the tags, so the snippet can be escaped before
+// the tags are added. They are private use characters, which don't
+// appear in ordinary text.
+const (
+	SnippetStart = "\uE000"
+	SnippetStop  = "\uE001"
+)
+
+var snippetTags = strings.NewReplacer(SnippetStart, "<mark>", SnippetStop, "</mark>")
+
+// EscapeSnippet HTML-escapes the text of the snippet and replaces
+// SnippetStart and SnippetStop with <mark> tags.
+func EscapeSnippet(snippet string) string {
+	return snippetTags.Replace(html.EscapeString(snippet))
+}
@@ -0,0 +1,59 @@
+package sqlitedb
+
+import (
+	"strings"
+
+	"github.com/gengeo7/highlitent/storage/gormdb"
+	"github.com/glebarez/sqlite"
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+)
+
+// Db reuses the gorm queries of gormdb.Db and only replaces the parts
+// that depend on the sql dialect.
+type Db struct {
+	gormdb.Db
+}
+
+func NewDb() *Db {
+	return &Db{}
+}
+
+func (d *Db) Open(path string) error {
+	dsn := path
+	if strings.Contains(dsn, "?") {
+		dsn += "&"
+	} else {
+		dsn += "?"
+	}
+	dsn += "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: gormdb.NewLogger()})
+	if err != nil {
+		return err
+	}
+
+	sqlDb, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	// sqlite allows only one writer, and every connection to :memory: opens
+	// a separate database, so the pool is kept to a single connection
+	sqlDb.SetMaxIdleConns(1)
+	sqlDb.SetMaxOpenConns(1)
+	sqlDb.SetConnMaxLifetime(0)
+
+	d.Db.Db = db
+	d.SqlDb = sqlDb
+	return nil
+}
+
+func (d *Db) Migrate(migrationPath string) error {
+	err := goose.SetDialect("sqlite3")
+	if err != nil {
+		return err
+	}
+	d.MigrationPath = migrationPath
+	return goose.Up(d.SqlDb, migrationPath)
+}
@@ -0,0 +1,81 @@
+package sqlitedb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gengeo7/highlitent/storage/storagetest"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/search"
+	"github.com/gengeo7/highlitent/types/users"
+)
+
+func openTestDb(t *testing.T) *Db {
+	t.Helper()
+	db := NewDb()
+	if err := db.Open(":memory:"); err != nil {
+		t.Fatalf("Open() failed: %v", err)
+	}
+	t.Cleanup(func() { db.SqlDb.Close() })
+	if err := db.Migrate("../../migrations/sqlite"); err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+	return db
+}
+
+func TestStorage(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return openTestDb(t)
+	})
+}
+
+// TestSearchSyntax checks that the fts5 query syntax in the input is
+// searched as text.
+func TestSearchSyntax(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDb(t)
+	u, err := db.UserCreate(ctx, &users.UserDto{Name: "user", Email: "user@email.com"}, "hash")
+	if err != nil {
+		t.Fatalf("UserCreate() failed: %v", err)
+	}
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "Как настроить Postgres?"}, u.ID)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	if _, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "Postgres настраивается через postgresql.conf"}, int(q.ID), u.ID); err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+
+	for _, text := range []string{`настроить"`, "postgres OR NOT", "text:postgres*"} {
+		if _, err := db.Search(ctx, &search.SearchQuery{Text: text, Limit: 10}); err != nil {
+			t.Errorf("Search(%q) failed: %v", text, err)
+		}
+	}
+	page, err := db.Search(ctx, &search.SearchQuery{Text: `настроить"`, Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if page.Total != 1 {
+		t.Errorf("Search() total with fts5 syntax: %d, want: 1", page.Total)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDb(t)
+
+	if err := db.Ping(ctx); err != nil {
+		t.Fatalf("Ping() failed: %v", err)
+	}
+	got, err := db.Migrations(ctx)
+	if err != nil {
+		t.Fatalf("Migrations() failed: %v", err)
+	}
+	if got.Current == 0 || got.Current != got.Latest {
+		t.Errorf("Migrations() = %+v, want current at latest", got)
+	}
+	if stats := db.SqlDb.Stats(); stats.MaxOpenConnections != 1 {
+		t.Errorf("Stats() max open connections: %d, want: 1", stats.MaxOpenConnections)
+	}
+}
@@ -0,0 +1,72 @@
+package sqlitedb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gengeo7/highlitent/storage"
+	"github.com/gengeo7/highlitent/types/search"
+)
+
+const searchHits = `
+select 'question' as type, questions.id as id, questions.id as question_id,
+    questions.created_at as created_at, -bm25(questions_fts) as rank,
+    snippet(questions_fts, 0, char(57344), char(57345), '…', 32) as snippet
+from questions_fts
+join questions on questions.id = questions_fts.rowid
+where questions_fts match @text and questions.deleted_at is null
+union all
+select 'answer' as type, answers.id as id, answers.question_id as question_id,
+    answers.created_at as created_at, -bm25(answers_fts) as rank,
+    snippet(answers_fts, 0, char(57344), char(57345), '…', 32) as snippet
+from answers_fts
+join answers on answers.id = answers_fts.rowid
+where answers_fts match @text and answers.deleted_at is null`
+
+const searchPage = searchHits + `
+order by rank desc, created_at desc, id desc
+limit @limit offset @offset`
+
+const searchCount = `select count(*) from (` + searchHits + `)`
+
+// matchQuery turns user input into an fts5 query where every word is
+// a quoted string, so the fts5 query syntax can't be injected.
+func matchQuery(text string) string {
+	words := strings.Fields(text)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+func (d *Db) Search(ctx context.Context, query *search.SearchQuery) (*search.SearchPage, error) {
+	args := map[string]any{
+		"text":   matchQuery(query.Text),
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	}
+
+	var page search.SearchPage
+	err := d.Db.Db.WithContext(ctx).
+		Raw(searchCount, args).
+		Scan(&page.Total).Error
+	if err != nil {
+		return nil, err
+	}
+
+	page.Results = make([]search.Result, 0)
+	if page.Total == 0 {
+		return &page, nil
+	}
+
+	err = d.Db.Db.WithContext(ctx).
+		Raw(searchPage, args).
+		Scan(&page.Results).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Results {
+		page.Results[i].Snippet = storage.EscapeSnippet(page.Results[i].Snippet)
+	}
+	return &page, nil
+}
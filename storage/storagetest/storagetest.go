@@ -0,0 +1,1188 @@
+// Package storagetest is the conformance suite every storage backend
+// is run against, so the backends behave the same way.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gengeo7/highlitent/storage"
+	answersStorage "github.com/gengeo7/highlitent/storage/answers"
+	commentsStorage "github.com/gengeo7/highlitent/storage/comments"
+	idempotencyStorage "github.com/gengeo7/highlitent/storage/idempotency"
+	questionsStorage "github.com/gengeo7/highlitent/storage/questions"
+	searchStorage "github.com/gengeo7/highlitent/storage/search"
+	tagsStorage "github.com/gengeo7/highlitent/storage/tags"
+	usersStorage "github.com/gengeo7/highlitent/storage/users"
+	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/gengeo7/highlitent/types/comments"
+	"github.com/gengeo7/highlitent/types/common"
+	"github.com/gengeo7/highlitent/types/idempotency"
+	"github.com/gengeo7/highlitent/types/questions"
+	"github.com/gengeo7/highlitent/types/search"
+	"github.com/gengeo7/highlitent/types/tags"
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+)
+
+// Storage is implemented by every backend of the app.
+type Storage interface {
+	questionsStorage.Storage
+	answersStorage.Storage
+	commentsStorage.Storage
+	searchStorage.Storage
+	tagsStorage.Storage
+	usersStorage.Storage
+	idempotencyStorage.Storage
+}
+
+// Run runs the suite, newDb must return an empty storage for every
+// test.
+func Run(t *testing.T, newDb func(t *testing.T) Storage) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, db Storage)
+	}{
+		{name: "AnswerCreate", run: testAnswerCreate},
+		{name: "QuestionDeleteCascade", run: testQuestionDeleteCascade},
+		{name: "CanceledContext", run: testCanceledContext},
+		{name: "QuestionUpdate", run: testQuestionUpdate},
+		{name: "QuestionUpdateConcurrent", run: testQuestionUpdateConcurrent},
+		{name: "QuestionsGetPagination", run: testQuestionsGetPagination},
+		{name: "QuestionGetAnswersPagination", run: testQuestionGetAnswersPagination},
+		{name: "Search", run: testSearch},
+		{name: "UserCreate", run: testUserCreate},
+		{name: "ContentAuthor", run: testContentAuthor},
+		{name: "UserSetRole", run: testUserSetRole},
+		{name: "AnswerVote", run: testAnswerVote},
+		{name: "AnswerVoteConcurrent", run: testAnswerVoteConcurrent},
+		{name: "QuestionAcceptAnswer", run: testQuestionAcceptAnswer},
+		{name: "QuestionTags", run: testQuestionTags},
+		{name: "Comments", run: testComments},
+		{name: "Revisions", run: testRevisions},
+		{name: "SoftDelete", run: testSoftDelete},
+		{name: "Idempotency", run: testIdempotency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newDb(t))
+		})
+	}
+}
+
+func createTestUser(t *testing.T, db Storage) uuid.UUID {
+	t.Helper()
+	u, err := db.UserCreate(context.Background(), &users.UserDto{
+		Name:  "user",
+		Email: uuid.NewString() + "@email.com",
+	}, "hash")
+	if err != nil {
+		t.Fatalf("UserCreate() failed: %v", err)
+	}
+	return u.ID
+}
+
+func testAnswerCreate(t *testing.T, db Storage) {
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(context.Background(), &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		questionID int
+		userID     uuid.UUID
+		wantErr    error
+	}{
+		{
+			name:       "created",
+			questionID: int(q.ID),
+			userID:     user,
+			wantErr:    nil,
+		},
+		{
+			name:       "question not found",
+			questionID: int(q.ID) + 1,
+			userID:     user,
+			wantErr:    storage.ErrDbNotFound,
+		},
+		{
+			name:       "user not found",
+			questionID: int(q.ID),
+			userID:     uuid.New(),
+			wantErr:    storage.ErrDbUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dto := &answers.AnswerDto{Text: "answer"}
+			got, gotErr := db.AnswerCreate(context.Background(), dto, tt.questionID, tt.userID)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("AnswerCreate(): %v, want: %v", gotErr, tt.wantErr)
+			}
+			if gotErr != nil {
+				return
+			}
+			if got.QuestionID != tt.questionID || got.Text != dto.Text || got.UserID != tt.userID {
+				t.Errorf("AnswerCreate() mismatch: %+v", got)
+			}
+		})
+	}
+}
+
+func testQuestionDeleteCascade(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+
+	got, err := db.QuestionGet(ctx, int(q.ID), &answers.AnswersQuery{Limit: 10, Order: common.OrderAsc})
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	if got.AnswersTotal != 1 || len(got.Answers) != 1 || got.Answers[0].ID != a.ID {
+		t.Fatalf("QuestionGet() answers mismatch: %+v", got.Answers)
+	}
+
+	if err := db.QuestionDelete(ctx, int(q.ID)); err != nil {
+		t.Fatalf("QuestionDelete() failed: %v", err)
+	}
+	if _, err := db.QuestionGet(ctx, int(q.ID), &answers.AnswersQuery{Limit: 10, Order: common.OrderAsc}); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionGet() after delete: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerGet(ctx, int(a.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerGet() after question delete: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if err := db.QuestionDelete(ctx, int(q.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionDelete() twice: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+}
+
+func testCanceledContext(t *testing.T, db Storage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := db.QuestionsGet(ctx, &questions.QuestionsQuery{Limit: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("QuestionsGet(): %v, want: %v", err, context.Canceled)
+	}
+}
+
+func testQuestionUpdate(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+
+	got, err := db.QuestionUpdate(ctx, int(q.ID), &questions.QuestionUpdateDto{Text: "edited"}, user)
+	if err != nil {
+		t.Fatalf("QuestionUpdate() failed: %v", err)
+	}
+	if got.Text != "edited" {
+		t.Errorf("QuestionUpdate() text: %q, want: %q", got.Text, "edited")
+	}
+	if !got.UpdatedAt.After(q.UpdatedAt) {
+		t.Errorf("QuestionUpdate() updatedAt %v is not after %v", got.UpdatedAt, q.UpdatedAt)
+	}
+
+	// the same text is saved without a revision, but updatedAt moves,
+	// the pause keeps it apart at millisecond precision
+	time.Sleep(2 * time.Millisecond)
+	same, err := db.QuestionUpdate(ctx, int(q.ID), &questions.QuestionUpdateDto{Text: "edited"}, user)
+	if err != nil {
+		t.Fatalf("QuestionUpdate() same text failed: %v", err)
+	}
+	if !same.UpdatedAt.After(got.UpdatedAt) {
+		t.Errorf("QuestionUpdate() same text updatedAt %v is not after %v", same.UpdatedAt, got.UpdatedAt)
+	}
+	revs, err := db.QuestionRevisionsGet(ctx, int(q.ID))
+	if err != nil {
+		t.Fatalf("QuestionRevisionsGet() failed: %v", err)
+	}
+	if len(revs) != 1 {
+		t.Errorf("QuestionRevisionsGet() after same text: %d revisions, want: 1", len(revs))
+	}
+
+	a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	gotAnswer, err := db.AnswerUpdate(ctx, int(a.ID), &answers.AnswerUpdateDto{Text: "answer"}, user)
+	if err != nil {
+		t.Fatalf("AnswerUpdate() same text failed: %v", err)
+	}
+	if !gotAnswer.UpdatedAt.After(a.UpdatedAt) {
+		t.Errorf("AnswerUpdate() same text updatedAt %v is not after %v", gotAnswer.UpdatedAt, a.UpdatedAt)
+	}
+	answerRevs, err := db.AnswerRevisionsGet(ctx, int(a.ID))
+	if err != nil {
+		t.Fatalf("AnswerRevisionsGet() failed: %v", err)
+	}
+	if len(answerRevs) != 0 {
+		t.Errorf("AnswerRevisionsGet() after same text: %d revisions, want: 0", len(answerRevs))
+	}
+
+	_, err = db.QuestionUpdate(ctx, int(q.ID)+1, &questions.QuestionUpdateDto{Text: "edited"}, user)
+	if !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionUpdate() missing question: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+}
+
+// testQuestionUpdateConcurrent checks that every edit sent at once
+// saves the text it replaced as a revision.
+func testQuestionUpdateConcurrent(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+
+	texts := []string{"question"}
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := range 10 {
+		text := fmt.Sprintf("edit %d", i)
+		texts = append(texts, text)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := db.QuestionUpdate(ctx, int(q.ID), &questions.QuestionUpdateDto{Text: text}, user); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("QuestionUpdate() failed: %v", err)
+	}
+
+	got, err := db.QuestionGet(ctx, int(q.ID), &answers.AnswersQuery{Limit: 1, Order: common.OrderAsc})
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	revs, err := db.QuestionRevisionsGet(ctx, int(q.ID))
+	if err != nil {
+		t.Fatalf("QuestionRevisionsGet() failed: %v", err)
+	}
+	gotTexts := []string{got.Question.Text}
+	for _, rev := range revs {
+		gotTexts = append(gotTexts, rev.Text)
+	}
+	if diff := cmp.Diff(texts, gotTexts, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("QuestionRevisionsGet() mismatch:\n %s", diff)
+	}
+}
+
+func testQuestionsGetPagination(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	ids := make([]uint, 0)
+	for range 5 {
+		q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+		if err != nil {
+			t.Fatalf("QuestionCreate() failed: %v", err)
+		}
+		ids = append(ids, q.ID)
+	}
+
+	tests := []struct {
+		name string
+		sort common.Order
+		want []uint
+	}{
+		{
+			name: "asc",
+			sort: common.OrderAsc,
+			want: ids,
+		},
+		{
+			name: "desc",
+			sort: common.OrderDesc,
+			want: []uint{ids[4], ids[3], ids[2], ids[1], ids[0]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]uint, 0)
+			query := &questions.QuestionsQuery{Limit: 2, Sort: tt.sort}
+			for pages := 0; ; pages++ {
+				if pages > len(tt.want) {
+					t.Fatal("QuestionsGet() does not stop paginating")
+				}
+				page, err := db.QuestionsGet(ctx, query)
+				if err != nil {
+					t.Fatalf("QuestionsGet() failed: %v", err)
+				}
+				for _, q := range page.Questions {
+					got = append(got, q.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				query.Cursor, err = common.DecodeCursor(page.NextCursor)
+				if err != nil {
+					t.Fatalf("DecodeCursor() failed: %v", err)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("QuestionsGet() mismatch:\n %s", diff)
+			}
+		})
+	}
+}
+
+func testQuestionGetAnswersPagination(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	ids := make([]uint, 0)
+	for range 3 {
+		a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), user)
+		if err != nil {
+			t.Fatalf("AnswerCreate() failed: %v", err)
+		}
+		ids = append(ids, a.ID)
+	}
+
+	query := &answers.AnswersQuery{Limit: 2, Order: common.OrderDesc}
+	first, err := db.QuestionGet(ctx, int(q.ID), query)
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	if first.AnswersTotal != 3 || first.NextAnswersCursor == "" {
+		t.Fatalf("QuestionGet() first page: total %d, cursor %q", first.AnswersTotal, first.NextAnswersCursor)
+	}
+
+	query.Cursor, err = common.DecodeCursor(first.NextAnswersCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() failed: %v", err)
+	}
+	second, err := db.QuestionGet(ctx, int(q.ID), query)
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	if second.NextAnswersCursor != "" {
+		t.Errorf("QuestionGet() last page has cursor %q", second.NextAnswersCursor)
+	}
+
+	got := make([]uint, 0)
+	for _, a := range append(first.Answers, second.Answers...) {
+		got = append(got, a.ID)
+	}
+	if diff := cmp.Diff([]uint{ids[2], ids[1], ids[0]}, got); diff != "" {
+		t.Errorf("QuestionGet() mismatch:\n %s", diff)
+	}
+}
+
+func testSearch(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "Как настроить Postgres?"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	_, err = db.AnswerCreate(ctx, &answers.AnswerDto{Text: "Postgres настраивается через postgresql.conf"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		query     *search.SearchQuery
+		wantTotal int64
+		want      []string
+	}{
+		{
+			name:      "question and answer",
+			query:     &search.SearchQuery{Text: "POSTGRES", Limit: 10},
+			wantTotal: 2,
+			want: []string{
+				"Как настроить <mark>Postgres</mark>?",
+				"<mark>Postgres</mark> настраивается через postgresql.conf",
+			},
+		},
+		{
+			name:      "all words must match",
+			query:     &search.SearchQuery{Text: "postgres настроить", Limit: 10},
+			wantTotal: 1,
+			want: []string{
+				"Как <mark>настроить</mark> <mark>Postgres</mark>?",
+			},
+		},
+		{
+			name:      "no words",
+			query:     &search.SearchQuery{Text: "?!", Limit: 10},
+			wantTotal: 0,
+			want:      []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := db.Search(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Search() failed: %v", err)
+			}
+			if page.Total != tt.wantTotal {
+				t.Errorf("Search() total: %d, want: %d", page.Total, tt.wantTotal)
+			}
+			got := make([]string, 0)
+			for _, r := range page.Results {
+				got = append(got, r.Snippet)
+			}
+			// the backends rank the results differently
+			if diff := cmp.Diff(tt.want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("Search() mismatch:\n %s", diff)
+			}
+		})
+	}
+
+	got := make([]string, 0)
+	var page *search.SearchPage
+	for offset := range 3 {
+		page, err = db.Search(ctx, &search.SearchQuery{Text: "postgres", Limit: 1, Offset: offset})
+		if err != nil {
+			t.Fatalf("Search() failed: %v", err)
+		}
+		if page.Total != 2 {
+			t.Errorf("Search() total with offset %d: %d, want: 2", offset, page.Total)
+		}
+		for _, r := range page.Results {
+			got = append(got, r.Snippet)
+		}
+	}
+	if len(got) != 2 || got[0] == got[1] {
+		t.Errorf("Search() pages mismatch: %q", got)
+	}
+
+	// the text is escaped around and inside the tags
+	_, err = db.QuestionCreate(ctx, &questions.QuestionDto{Text: `Почему <b>Postgres</b> & <script>alert("x")</script>?`}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	page, err = db.Search(ctx, &search.SearchQuery{Text: "почему", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	want := "<mark>Почему</mark> &lt;b&gt;Postgres&lt;/b&gt; &amp; &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;?"
+	if len(page.Results) != 1 || page.Results[0].Snippet != want {
+		t.Errorf("Search() escaped snippet: %+v, want: %q", page.Results, want)
+	}
+}
+
+func testUserCreate(t *testing.T, db Storage) {
+	ctx := context.Background()
+	dto := &users.UserDto{Name: "user", Email: "user@email.com"}
+	u, err := db.UserCreate(ctx, dto, "hash")
+	if err != nil {
+		t.Fatalf("UserCreate() failed: %v", err)
+	}
+	if _, err := db.UserCreate(ctx, dto, "hash"); !errors.Is(err, storage.ErrDbAlreadyExists) {
+		t.Errorf("UserCreate() same email: %v, want: %v", err, storage.ErrDbAlreadyExists)
+	}
+
+	got, err := db.UserGet(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("UserGet() failed: %v", err)
+	}
+	if got.Email != dto.Email || got.PasswordHash != "hash" || got.Role != users.RoleUser {
+		t.Errorf("UserGet() mismatch: %+v", got)
+	}
+	if _, err := db.UserGet(ctx, uuid.New()); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("UserGet() missing user: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+
+	if _, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, uuid.New()); !errors.Is(err, storage.ErrDbUserNotFound) {
+		t.Errorf("QuestionCreate() missing user: %v, want: %v", err, storage.ErrDbUserNotFound)
+	}
+}
+
+func testContentAuthor(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+
+	questionAuthor, err := db.QuestionAuthor(ctx, int(q.ID))
+	if err != nil {
+		t.Fatalf("QuestionAuthor() failed: %v", err)
+	}
+	if questionAuthor == nil || *questionAuthor != user {
+		t.Errorf("QuestionAuthor() = %v, want: %v", questionAuthor, user)
+	}
+	answerAuthor, err := db.AnswerAuthor(ctx, int(a.ID))
+	if err != nil {
+		t.Fatalf("AnswerAuthor() failed: %v", err)
+	}
+	if answerAuthor == nil || *answerAuthor != user {
+		t.Errorf("AnswerAuthor() = %v, want: %v", answerAuthor, user)
+	}
+
+	if _, err := db.QuestionAuthor(ctx, int(q.ID)+1); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionAuthor() missing question: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerAuthor(ctx, int(a.ID)+1); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerAuthor() missing answer: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+}
+
+func testUserSetRole(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+
+	u, err := db.UserSetRole(ctx, user, users.RoleModerator)
+	if err != nil {
+		t.Fatalf("UserSetRole() failed: %v", err)
+	}
+	if u.ID != user || u.Role != users.RoleModerator {
+		t.Errorf("UserSetRole() = %+v, want role: %q", u, users.RoleModerator)
+	}
+	got, err := db.UserGet(ctx, user)
+	if err != nil {
+		t.Fatalf("UserGet() failed: %v", err)
+	}
+	if got.Role != users.RoleModerator {
+		t.Errorf("UserGet() role: %q, want: %q", got.Role, users.RoleModerator)
+	}
+
+	if _, err := db.UserSetRole(ctx, uuid.New(), users.RoleAdmin); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("UserSetRole() missing user: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+}
+
+func testAnswerVote(t *testing.T, db Storage) {
+	ctx := context.Background()
+	author := createTestUser(t, db)
+	voter := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, author)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	ids := make([]uint, 0)
+	for range 3 {
+		a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), author)
+		if err != nil {
+			t.Fatalf("AnswerCreate() failed: %v", err)
+		}
+		ids = append(ids, a.ID)
+	}
+
+	votes := []struct {
+		answerID  uint
+		userID    uuid.UUID
+		value     int
+		wantScore int
+	}{
+		{answerID: ids[1], userID: voter, value: 1, wantScore: 1},
+		{answerID: ids[1], userID: voter, value: 1, wantScore: 1},
+		{answerID: ids[1], userID: author, value: 1, wantScore: 2},
+		{answerID: ids[2], userID: voter, value: 1, wantScore: 1},
+		{answerID: ids[0], userID: voter, value: 1, wantScore: 1},
+		{answerID: ids[0], userID: voter, value: -1, wantScore: -1},
+	}
+	for _, v := range votes {
+		a, err := db.AnswerVote(ctx, int(v.answerID), v.userID, v.value)
+		if err != nil {
+			t.Fatalf("AnswerVote() failed: %v", err)
+		}
+		if a.Score != v.wantScore {
+			t.Errorf("AnswerVote() score of %d: %d, want: %d", v.answerID, a.Score, v.wantScore)
+		}
+	}
+
+	if _, err := db.AnswerVote(ctx, int(ids[2])+1, voter, 1); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerVote() missing answer: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerVote(ctx, int(ids[0]), uuid.New(), 1); !errors.Is(err, storage.ErrDbUserNotFound) {
+		t.Errorf("AnswerVote() missing user: %v, want: %v", err, storage.ErrDbUserNotFound)
+	}
+
+	query := &answers.AnswersQuery{Limit: 2, Order: answers.OrderScore}
+	first, err := db.QuestionGet(ctx, int(q.ID), query)
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	query.Cursor, err = common.DecodeCursor(first.NextAnswersCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() failed: %v", err)
+	}
+	second, err := db.QuestionGet(ctx, int(q.ID), query)
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	if second.NextAnswersCursor != "" {
+		t.Errorf("QuestionGet() last page has cursor %q", second.NextAnswersCursor)
+	}
+
+	got := make([]uint, 0)
+	for _, a := range append(first.Answers, second.Answers...) {
+		got = append(got, a.ID)
+	}
+	if diff := cmp.Diff([]uint{ids[1], ids[2], ids[0]}, got); diff != "" {
+		t.Errorf("QuestionGet() mismatch:\n %s", diff)
+	}
+}
+
+// testAnswerVoteConcurrent checks that votes sent at once are neither
+// lost nor counted twice.
+func testAnswerVoteConcurrent(t *testing.T, db Storage) {
+	ctx := context.Background()
+	author := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, author)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), author)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+	voters := make([]uuid.UUID, 0)
+	for range 10 {
+		voters = append(voters, createTestUser(t, db))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*len(voters))
+	for _, voter := range voters {
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := db.AnswerVote(ctx, int(a.ID), voter, 1); err != nil {
+					errs <- err
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("AnswerVote() failed: %v", err)
+	}
+
+	got, err := db.AnswerGet(ctx, int(a.ID))
+	if err != nil {
+		t.Fatalf("AnswerGet() failed: %v", err)
+	}
+	if got.Score != len(voters) {
+		t.Errorf("AnswerGet() score: %d, want: %d", got.Score, len(voters))
+	}
+}
+
+func testQuestionAcceptAnswer(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	other, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "other"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	ids := make([]uint, 0)
+	for range 3 {
+		a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), user)
+		if err != nil {
+			t.Fatalf("AnswerCreate() failed: %v", err)
+		}
+		ids = append(ids, a.ID)
+	}
+	firstID, lastID := int(ids[0]), int(ids[2])
+
+	if _, err := db.QuestionAcceptAnswer(ctx, int(other.ID), &firstID); !errors.Is(err, storage.ErrDbAnswerNotFound) {
+		t.Errorf("QuestionAcceptAnswer() answer of other question: %v, want: %v", err, storage.ErrDbAnswerNotFound)
+	}
+	if _, err := db.QuestionAcceptAnswer(ctx, int(other.ID)+1, nil); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionAcceptAnswer() missing question: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+
+	got, err := db.QuestionAcceptAnswer(ctx, int(q.ID), &lastID)
+	if err != nil {
+		t.Fatalf("QuestionAcceptAnswer() failed: %v", err)
+	}
+	if diff := cmp.Diff(&ids[2], got.AcceptedAnswerID); diff != "" {
+		t.Errorf("QuestionAcceptAnswer() mismatch:\n %s", diff)
+	}
+
+	// the accepted answer takes a place on the first page, every
+	// answer is still listed once
+	for _, limit := range []int{1, 2, 3} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			query := &answers.AnswersQuery{Limit: limit, Order: common.OrderAsc}
+			gotIDs := make([]uint, 0)
+			for pages := 0; ; pages++ {
+				if pages > len(ids) {
+					t.Fatal("QuestionGet() does not stop paginating")
+				}
+				page, err := db.QuestionGet(ctx, int(q.ID), query)
+				if err != nil {
+					t.Fatalf("QuestionGet() failed: %v", err)
+				}
+				if len(page.Answers) > limit {
+					t.Errorf("QuestionGet() page of %d answers, want at most %d", len(page.Answers), limit)
+				}
+				for _, a := range page.Answers {
+					gotIDs = append(gotIDs, a.ID)
+				}
+				if page.NextAnswersCursor == "" {
+					break
+				}
+				query.Cursor, err = common.DecodeCursor(page.NextAnswersCursor)
+				if err != nil {
+					t.Fatalf("DecodeCursor() failed: %v", err)
+				}
+			}
+			if diff := cmp.Diff([]uint{ids[2], ids[0], ids[1]}, gotIDs); diff != "" {
+				t.Errorf("QuestionGet() mismatch:\n %s", diff)
+			}
+		})
+	}
+
+	if err := db.AnswerDelete(ctx, int(ids[2])); err != nil {
+		t.Fatalf("AnswerDelete() failed: %v", err)
+	}
+	page, err := db.QuestionsGet(ctx, &questions.QuestionsQuery{Limit: 10, Sort: common.OrderAsc})
+	if err != nil {
+		t.Fatalf("QuestionsGet() failed: %v", err)
+	}
+	if page.Questions[0].AcceptedAnswerID != nil {
+		t.Errorf("QuestionsGet() deleted answer is still accepted: %d", *page.Questions[0].AcceptedAnswerID)
+	}
+
+	if _, err := db.QuestionAcceptAnswer(ctx, int(q.ID), &firstID); err != nil {
+		t.Fatalf("QuestionAcceptAnswer() failed: %v", err)
+	}
+	got, err = db.QuestionAcceptAnswer(ctx, int(q.ID), nil)
+	if err != nil {
+		t.Fatalf("QuestionAcceptAnswer() failed: %v", err)
+	}
+	if got.AcceptedAnswerID != nil {
+		t.Errorf("QuestionAcceptAnswer() unaccept left %d", *got.AcceptedAnswerID)
+	}
+}
+
+func testQuestionTags(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	dtos := []*questions.QuestionDto{
+		{Text: "first", Tags: []string{"postgres", "go"}},
+		{Text: "second", Tags: []string{"go"}},
+		{Text: "third"},
+	}
+	ids := make([]uint, 0)
+	for _, dto := range dtos {
+		q, err := db.QuestionCreate(ctx, dto, user)
+		if err != nil {
+			t.Fatalf("QuestionCreate() failed: %v", err)
+		}
+		ids = append(ids, q.ID)
+	}
+
+	got, err := db.QuestionGet(ctx, int(ids[0]), &answers.AnswersQuery{Limit: 1, Order: common.OrderAsc})
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"go", "postgres"}, got.Question.Tags); diff != "" {
+		t.Errorf("QuestionGet() tags mismatch:\n %s", diff)
+	}
+
+	tests := []struct {
+		tags []string
+		want []uint
+	}{
+		{tags: nil, want: []uint{ids[0], ids[1], ids[2]}},
+		{tags: []string{"go"}, want: []uint{ids[0], ids[1]}},
+		{tags: []string{"go", "postgres"}, want: []uint{ids[0]}},
+		{tags: []string{"rust"}, want: []uint{}},
+	}
+	for _, tt := range tests {
+		page, err := db.QuestionsGet(ctx, &questions.QuestionsQuery{Limit: 10, Sort: common.OrderAsc, Tags: tt.tags})
+		if err != nil {
+			t.Fatalf("QuestionsGet() failed: %v", err)
+		}
+		gotIDs := make([]uint, 0)
+		for _, q := range page.Questions {
+			gotIDs = append(gotIDs, q.ID)
+		}
+		if diff := cmp.Diff(tt.want, gotIDs); diff != "" {
+			t.Errorf("QuestionsGet() with tags %v mismatch:\n %s", tt.tags, diff)
+		}
+	}
+
+	if err := db.QuestionDelete(ctx, int(ids[1])); err != nil {
+		t.Fatalf("QuestionDelete() failed: %v", err)
+	}
+	ts, err := db.TagsGet(ctx, &tags.TagsQuery{Limit: 10})
+	if err != nil {
+		t.Fatalf("TagsGet() failed: %v", err)
+	}
+	want := []tags.TagWithCount{{Name: "go", Questions: 1}, {Name: "postgres", Questions: 1}}
+	if diff := cmp.Diff(want, ts); diff != "" {
+		t.Errorf("TagsGet() mismatch:\n %s", diff)
+	}
+}
+
+func testComments(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+	other, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "other"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+
+	root, err := db.CommentCreate(ctx, &comments.CommentDto{Text: "root"}, int(a.ID), user)
+	if err != nil {
+		t.Fatalf("CommentCreate() failed: %v", err)
+	}
+	reply, err := db.CommentCreate(ctx, &comments.CommentDto{Text: "reply", ParentID: &root.ID}, int(a.ID), user)
+	if err != nil {
+		t.Fatalf("CommentCreate() failed: %v", err)
+	}
+
+	if _, err := db.CommentCreate(ctx, &comments.CommentDto{Text: "reply", ParentID: &root.ID}, int(other.ID), user); !errors.Is(err, storage.ErrDbCommentNotFound) {
+		t.Errorf("CommentCreate() parent under other answer: %v, want: %v", err, storage.ErrDbCommentNotFound)
+	}
+	if _, err := db.CommentCreate(ctx, &comments.CommentDto{Text: "root"}, int(other.ID)+1, user); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("CommentCreate() missing answer: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.CommentCreate(ctx, &comments.CommentDto{Text: "root"}, int(a.ID), uuid.New()); !errors.Is(err, storage.ErrDbUserNotFound) {
+		t.Errorf("CommentCreate() missing user: %v, want: %v", err, storage.ErrDbUserNotFound)
+	}
+
+	cs, err := db.CommentsGet(ctx, int(a.ID))
+	if err != nil {
+		t.Fatalf("CommentsGet() failed: %v", err)
+	}
+	got := make([]uint, 0)
+	for _, c := range cs {
+		got = append(got, c.ID)
+	}
+	if diff := cmp.Diff([]uint{root.ID, reply.ID}, got); diff != "" {
+		t.Errorf("CommentsGet() mismatch:\n %s", diff)
+	}
+
+	if err := db.AnswerDelete(ctx, int(a.ID)); err != nil {
+		t.Fatalf("AnswerDelete() failed: %v", err)
+	}
+	if _, err := db.CommentsGet(ctx, int(a.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("CommentsGet() deleted answer: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.CommentCreate(ctx, &comments.CommentDto{Text: "reply", ParentID: &root.ID}, int(other.ID), user); !errors.Is(err, storage.ErrDbCommentNotFound) {
+		t.Errorf("CommentCreate() reply to deleted comment: %v, want: %v", err, storage.ErrDbCommentNotFound)
+	}
+}
+
+func testRevisions(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	editor := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "first"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	a, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "answer"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+
+	for _, text := range []string{"second", "second", "third"} {
+		_, err := db.QuestionUpdate(ctx, int(q.ID), &questions.QuestionUpdateDto{Text: text, Reason: "typo"}, editor)
+		if err != nil {
+			t.Fatalf("QuestionUpdate() failed: %v", err)
+		}
+	}
+	revs, err := db.QuestionRevisionsGet(ctx, int(q.ID))
+	if err != nil {
+		t.Fatalf("QuestionRevisionsGet() failed: %v", err)
+	}
+	// the same text is not saved twice, the newest goes first
+	if len(revs) != 2 || revs[0].Text != "second" || revs[1].Text != "first" {
+		t.Fatalf("QuestionRevisionsGet() mismatch: %+v", revs)
+	}
+	if revs[0].EditorID == nil || *revs[0].EditorID != editor || revs[0].Reason != "typo" {
+		t.Errorf("QuestionRevisionsGet() editor or reason mismatch: %+v", revs[0])
+	}
+
+	rev, err := db.QuestionRevisionGet(ctx, int(q.ID), int(revs[1].ID))
+	if err != nil {
+		t.Fatalf("QuestionRevisionGet() failed: %v", err)
+	}
+	if rev.Text != "first" {
+		t.Errorf("QuestionRevisionGet() text: %q, want: %q", rev.Text, "first")
+	}
+
+	_, err = db.AnswerUpdate(ctx, int(a.ID), &answers.AnswerUpdateDto{Text: "edited"}, editor)
+	if err != nil {
+		t.Fatalf("AnswerUpdate() failed: %v", err)
+	}
+	answerRevs, err := db.AnswerRevisionsGet(ctx, int(a.ID))
+	if err != nil {
+		t.Fatalf("AnswerRevisionsGet() failed: %v", err)
+	}
+	if len(answerRevs) != 1 || answerRevs[0].Text != "answer" {
+		t.Fatalf("AnswerRevisionsGet() mismatch: %+v", answerRevs)
+	}
+
+	// a revision is only found under its own question or answer
+	_, err = db.AnswerRevisionGet(ctx, int(a.ID), int(answerRevs[0].ID))
+	if err != nil {
+		t.Fatalf("AnswerRevisionGet() failed: %v", err)
+	}
+	_, err = db.QuestionRevisionGet(ctx, int(q.ID)+1, int(revs[1].ID))
+	if !errors.Is(err, storage.ErrDbRevisionNotFound) {
+		t.Errorf("QuestionRevisionGet() of another question: %v, want: %v", err, storage.ErrDbRevisionNotFound)
+	}
+	_, err = db.QuestionRevisionsGet(ctx, int(q.ID)+1)
+	if !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionRevisionsGet() missing question: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+
+	if err := db.QuestionPurge(ctx, int(q.ID)); err != nil {
+		t.Fatalf("QuestionPurge() failed: %v", err)
+	}
+	_, err = db.QuestionRevisionGet(ctx, int(q.ID), int(revs[1].ID))
+	if !errors.Is(err, storage.ErrDbRevisionNotFound) {
+		t.Errorf("QuestionRevisionGet() after purge: %v, want: %v", err, storage.ErrDbRevisionNotFound)
+	}
+	_, err = db.AnswerRevisionGet(ctx, int(a.ID), int(answerRevs[0].ID))
+	if !errors.Is(err, storage.ErrDbRevisionNotFound) {
+		t.Errorf("AnswerRevisionGet() after purge: %v, want: %v", err, storage.ErrDbRevisionNotFound)
+	}
+}
+
+func testSoftDelete(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	q, err := db.QuestionCreate(ctx, &questions.QuestionDto{Text: "question"}, user)
+	if err != nil {
+		t.Fatalf("QuestionCreate() failed: %v", err)
+	}
+	kept, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "kept"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+	removed, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "removed"}, int(q.ID), user)
+	if err != nil {
+		t.Fatalf("AnswerCreate() failed: %v", err)
+	}
+	keptID := int(kept.ID)
+	if _, err := db.QuestionAcceptAnswer(ctx, int(q.ID), &keptID); err != nil {
+		t.Fatalf("QuestionAcceptAnswer() failed: %v", err)
+	}
+
+	// the answer deleted on its own must stay in the trash
+	// when the question is restored
+	if err := db.AnswerDelete(ctx, int(removed.ID)); err != nil {
+		t.Fatalf("AnswerDelete() failed: %v", err)
+	}
+	if err := db.QuestionDelete(ctx, int(q.ID)); err != nil {
+		t.Fatalf("QuestionDelete() failed: %v", err)
+	}
+	if _, err := db.QuestionGet(ctx, int(q.ID), &answers.AnswersQuery{Limit: 10, Order: common.OrderAsc}); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionGet() in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerGet(ctx, keptID); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerGet() in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	page, err := db.QuestionsGet(ctx, &questions.QuestionsQuery{Limit: 10, Sort: common.OrderAsc})
+	if err != nil {
+		t.Fatalf("QuestionsGet() failed: %v", err)
+	}
+	if len(page.Questions) != 0 {
+		t.Errorf("QuestionsGet() returned questions in trash: %+v", page.Questions)
+	}
+	if _, err := db.QuestionAuthor(ctx, int(q.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionAuthor() in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.QuestionAuthorUnscoped(ctx, int(q.ID)); err != nil {
+		t.Errorf("QuestionAuthorUnscoped() in trash failed: %v", err)
+	}
+	if _, err := db.AnswerAuthor(ctx, keptID); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerAuthor() in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerAuthorUnscoped(ctx, keptID); err != nil {
+		t.Errorf("AnswerAuthorUnscoped() in trash failed: %v", err)
+	}
+	if _, err := db.QuestionRevisionsGet(ctx, int(q.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionRevisionsGet() in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.QuestionRevisionsGetUnscoped(ctx, int(q.ID)); err != nil {
+		t.Errorf("QuestionRevisionsGetUnscoped() in trash failed: %v", err)
+	}
+	if _, err := db.AnswerRevisionsGet(ctx, int(removed.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerRevisionsGet() in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerRevisionsGetUnscoped(ctx, int(removed.ID)); err != nil {
+		t.Errorf("AnswerRevisionsGetUnscoped() in trash failed: %v", err)
+	}
+	if _, err := db.AnswerCreate(ctx, &answers.AnswerDto{Text: "late"}, int(q.ID), user); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerCreate() to question in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerRestore(ctx, keptID); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerRestore() with question in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+
+	restored, err := db.QuestionRestore(ctx, int(q.ID))
+	if err != nil {
+		t.Fatalf("QuestionRestore() failed: %v", err)
+	}
+	if restored.AcceptedAnswerID == nil || *restored.AcceptedAnswerID != kept.ID {
+		t.Errorf("QuestionRestore() accepted answer: %v, want: %d", restored.AcceptedAnswerID, kept.ID)
+	}
+	got, err := db.QuestionGet(ctx, int(q.ID), &answers.AnswersQuery{Limit: 10, Order: common.OrderAsc})
+	if err != nil {
+		t.Fatalf("QuestionGet() failed: %v", err)
+	}
+	if len(got.Answers) != 1 || got.Answers[0].ID != kept.ID {
+		t.Errorf("QuestionGet() answers after restore: %+v", got.Answers)
+	}
+	if _, err := db.QuestionRestore(ctx, int(q.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionRestore() not in trash: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if _, err := db.AnswerRestore(ctx, int(removed.ID)); err != nil {
+		t.Errorf("AnswerRestore() failed: %v", err)
+	}
+
+	if err := db.QuestionPurge(ctx, int(q.ID)); err != nil {
+		t.Fatalf("QuestionPurge() failed: %v", err)
+	}
+	if _, err := db.QuestionAuthorUnscoped(ctx, int(q.ID)); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("QuestionAuthorUnscoped() after purge: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+	if err := db.AnswerPurge(ctx, keptID); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("AnswerPurge() after question purge: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+}
+
+func testIdempotency(t *testing.T, db Storage) {
+	ctx := context.Background()
+	user := createTestUser(t, db)
+	other := createTestUser(t, db)
+	reserve := func(userID uuid.UUID, key, fingerprint string) *idempotency.Record {
+		return &idempotency.Record{
+			UserID:      userID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			ExpiresAt:   time.Now().Add(time.Hour),
+			LockedUntil: time.Now().Add(time.Minute),
+		}
+	}
+
+	record := reserve(user, "key", "a")
+	if _, err := db.IdempotencyStart(ctx, record); err != nil {
+		t.Fatalf("IdempotencyStart() failed: %v", err)
+	}
+
+	// keys are kept per user
+	if _, err := db.IdempotencyStart(ctx, reserve(other, "key", "b")); err != nil {
+		t.Fatalf("IdempotencyStart() other user failed: %v", err)
+	}
+
+	existing, err := db.IdempotencyStart(ctx, reserve(user, "key", "c"))
+	if !errors.Is(err, storage.ErrDbAlreadyExists) {
+		t.Fatalf("IdempotencyStart() same key: %v, want: %v", err, storage.ErrDbAlreadyExists)
+	}
+	if existing.Fingerprint != "a" || existing.Status != 0 {
+		t.Errorf("IdempotencyStart() same key = %+v, want the record in progress", existing)
+	}
+
+	if err := db.IdempotencyFinish(ctx, user, "key", 201, "application/json", []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("IdempotencyFinish() failed: %v", err)
+	}
+	existing, err = db.IdempotencyStart(ctx, reserve(user, "key", "a"))
+	if !errors.Is(err, storage.ErrDbAlreadyExists) {
+		t.Fatalf("IdempotencyStart() finished key: %v, want: %v", err, storage.ErrDbAlreadyExists)
+	}
+	want := idempotency.Record{UserID: user, Key: "key", Fingerprint: "a", Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+	opts := cmpopts.IgnoreFields(idempotency.Record{}, "CreatedAt", "ExpiresAt", "LockedUntil")
+	if diff := cmp.Diff(want, *existing, opts); diff != "" {
+		t.Errorf("IdempotencyStart() finished key mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := db.IdempotencyFinish(ctx, user, "missing", 201, "", nil); !errors.Is(err, storage.ErrDbNotFound) {
+		t.Errorf("IdempotencyFinish() missing key: %v, want: %v", err, storage.ErrDbNotFound)
+	}
+
+	// an expired key can be used again
+	expired := reserve(user, "expired", "a")
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := db.IdempotencyStart(ctx, expired); err != nil {
+		t.Fatalf("IdempotencyStart() failed: %v", err)
+	}
+	if _, err := db.IdempotencyStart(ctx, reserve(user, "expired", "b")); err != nil {
+		t.Errorf("IdempotencyStart() expired key: %v", err)
+	}
+
+	// a key in progress past its lock is taken by the next request, a
+	// finished one is not
+	locked := reserve(user, "locked", "a")
+	locked.LockedUntil = time.Now().Add(-time.Second)
+	if _, err := db.IdempotencyStart(ctx, locked); err != nil {
+		t.Fatalf("IdempotencyStart() failed: %v", err)
+	}
+	if _, err := db.IdempotencyStart(ctx, reserve(user, "locked", "b")); err != nil {
+		t.Fatalf("IdempotencyStart() expired lock: %v", err)
+	}
+	existing, err = db.IdempotencyStart(ctx, reserve(user, "locked", "c"))
+	if !errors.Is(err, storage.ErrDbAlreadyExists) {
+		t.Fatalf("IdempotencyStart() taken key: %v, want: %v", err, storage.ErrDbAlreadyExists)
+	}
+	if existing.Fingerprint != "b" || existing.Status != 0 {
+		t.Errorf("IdempotencyStart() taken key = %+v, want the record of the takeover", existing)
+	}
+	finished := reserve(user, "finished", "a")
+	finished.LockedUntil = time.Now().Add(-time.Second)
+	if _, err := db.IdempotencyStart(ctx, finished); err != nil {
+		t.Fatalf("IdempotencyStart() failed: %v", err)
+	}
+	if err := db.IdempotencyFinish(ctx, user, "finished", 201, "application/json", nil); err != nil {
+		t.Fatalf("IdempotencyFinish() failed: %v", err)
+	}
+	if _, err := db.IdempotencyStart(ctx, reserve(user, "finished", "b")); !errors.Is(err, storage.ErrDbAlreadyExists) {
+		t.Errorf("IdempotencyStart() finished key past its lock: %v, want: %v", err, storage.ErrDbAlreadyExists)
+	}
+
+	if err := db.IdempotencyDelete(ctx, user, "key"); err != nil {
+		t.Fatalf("IdempotencyDelete() failed: %v", err)
+	}
+	if _, err := db.IdempotencyStart(ctx, reserve(user, "key", "d")); err != nil {
+		t.Errorf("IdempotencyStart() deleted key: %v", err)
+	}
+
+	if _, err := db.IdempotencyStart(ctx, reserve(uuid.New(), "key", "a")); !errors.Is(err, storage.ErrDbUserNotFound) {
+		t.Errorf("IdempotencyStart() unknown user: %v, want: %v", err, storage.ErrDbUserNotFound)
+	}
+}
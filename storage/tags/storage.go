@@ -0,0 +1,11 @@
+package tags
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/tags"
+)
+
+type Storage interface {
+	TagsGet(ctx context.Context, query *tags.TagsQuery) ([]tags.TagWithCount, error)
+}
@@ -0,0 +1,16 @@
+package users
+
+import (
+	"context"
+
+	"github.com/gengeo7/highlitent/types/users"
+	"github.com/google/uuid"
+)
+
+type Storage interface {
+	UserCreate(ctx context.Context, dto *users.UserDto, passwordHash string) (*users.User, error)
+	UserGet(ctx context.Context, id uuid.UUID) (*users.User, error)
+	UserGetByEmail(ctx context.Context, email string) (*users.User, error)
+	UsersGet(ctx context.Context, query *users.UsersQuery) ([]users.User, error)
+	UserSetRole(ctx context.Context, id uuid.UUID, role users.Role) (*users.User, error)
+}
@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/gengeo7/highlitent/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/gengeo7/highlitent"
+
+var provider *sdktrace.TracerProvider
+
+// file is the TRACING_FILE, it is closed after the provider flushed
+// the spans into it.
+var file *os.File
+
+// Init sets up the W3C traceparent propagation and the exporter chosen
+// by config.Conf.TracingExporter. Without an exporter spans are not
+// recorded, but the incoming trace context is still passed on.
+func Init() error {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.Conf.TracingExporter {
+	case config.TracingStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case config.TracingFile:
+		f, openErr := os.OpenFile(config.Conf.TracingFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if openErr != nil {
+			return openErr
+		}
+		file = f
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	case config.TracingOtlp:
+		// the exporter connects lazily, a collector that is down
+		// doesn't stop the server, the spans are dropped
+		exporter, err = otlptracehttp.New(
+			context.Background(),
+			otlptracehttp.WithEndpointURL(config.Conf.TracingOtlpEndpoint),
+		)
+	default:
+		return nil
+	}
+	if err != nil {
+		closeFile()
+		return err
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName("highlitent"))),
+	)
+	otel.SetTracerProvider(provider)
+	return nil
+}
+
+// Shutdown exports the spans that are still buffered and closes the
+// TRACING_FILE.
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	err := provider.Shutdown(ctx)
+	if closeErr := closeFile(); closeErr != nil {
+		return errors.Join(err, closeErr)
+	}
+	return err
+}
+
+func closeFile() error {
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	return err
+}
+
+// Start starts a span named name as a child of the span in ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
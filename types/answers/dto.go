@@ -1,8 +1,20 @@
 package answers
 
-import "github.com/google/uuid"
-
 type AnswerDto struct {
-	UserID uuid.UUID `json:"userID" validate:"required,uuid"`
-	Text   string    `json:"text" validate:"required"`
+	Text string `json:"text" validate:"required"`
+}
+
+type AnswerUpdateDto struct {
+	Text   string `json:"text" validate:"required"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+type VoteDto struct {
+	Value int `json:"value" validate:"oneof=-1 1"`
+}
+
+// RollbackDto is the optional body of a rollback, Reason is saved
+// with the revision of the replaced text.
+type RollbackDto struct {
+	Reason string `json:"reason" validate:"max=500"`
 }
@@ -0,0 +1,18 @@
+package answers
+
+import "github.com/gengeo7/highlitent/types/common"
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// OrderScore sorts answers by score, best first, and by time
+// among answers with the same score.
+const OrderScore common.Order = "score"
+
+type AnswersQuery struct {
+	Limit  int
+	Cursor *common.Cursor
+	Order  common.Order
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Answer struct {
@@ -11,6 +12,39 @@ type Answer struct {
 	QuestionID int       `json:"questionID" gorm:"index;not null"`
 	UserID     uuid.UUID `json:"userID" gorm:"uuid;not null"`
 	Text       string    `json:"text" gorm:"type:text;not null"`
+	Score      int       `json:"score" gorm:"not null;default:0"`
 	CreatedAt  time.Time `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	// DeletedAt is set while the answer is in the trash, such
+	// answers are hidden from every read
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Revision is a previous text of an answer, saved when EditorID
+// replaced it with a new one.
+type Revision struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	AnswerID  uint       `json:"answerID" gorm:"not null"`
+	Text      string     `json:"text" gorm:"type:text;not null"`
+	EditorID  *uuid.UUID `json:"editorID" gorm:"type:uuid"`
+	Reason    string     `json:"reason" gorm:"type:text;not null"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+func (Revision) TableName() string {
+	return "answer_revisions"
+}
+
+// Vote is a single +1/-1 of a user on an answer, a user has at
+// most one vote per answer.
+type Vote struct {
+	AnswerID  uint      `json:"answerID" gorm:"primarykey"`
+	UserID    uuid.UUID `json:"userID" gorm:"type:uuid;primarykey"`
+	Value     int       `json:"value" gorm:"not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+func (Vote) TableName() string {
+	return "answer_votes"
 }
@@ -0,0 +1,6 @@
+package comments
+
+type CommentDto struct {
+	Text     string `json:"text" validate:"required,max=2000"`
+	ParentID *uint  `json:"parentID"`
+}
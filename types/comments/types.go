@@ -0,0 +1,23 @@
+package comments
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Comment struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	AnswerID  int       `json:"answerID" gorm:"index;not null"`
+	ParentID  *uint     `json:"parentID"`
+	UserID    uuid.UUID `json:"userID" gorm:"type:uuid;not null"`
+	Text      string    `json:"text" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// CommentTree is a comment with the replies to it, oldest first.
+type CommentTree struct {
+	Comment
+	Replies []*CommentTree `json:"replies"`
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// Cursor points at the last row of a page for keyset pagination
+// on (created_at, id). Score is set only for pages sorted by score.
+// Accepted is set when the page ended with the accepted answer put
+// first, the next page starts from the first of the other answers.
+type Cursor struct {
+	Score     *int      `json:"s,omitempty"`
+	CreatedAt time.Time `json:"c"`
+	ID        uint      `json:"i"`
+	Accepted  bool      `json:"a,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -5,3 +5,7 @@ type MessageDto struct {
 }
 
 type RequestIdKey struct{}
+
+type UserIdKey struct{}
+
+type UserRoleKey struct{}
@@ -0,0 +1,27 @@
+package health
+
+type Status string
+
+const (
+	StatusOk   Status = "ok"
+	StatusFail Status = "fail"
+)
+
+// Check is the result of checking one dependency. Details hold what
+// the check found, e.g. the migration versions.
+type Check struct {
+	Status  Status `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Details any    `json:"details,omitempty"`
+}
+
+// Report fails when any of its checks fails.
+type Report struct {
+	Status Status           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+type Migrations struct {
+	Current int64 `json:"current"`
+	Latest  int64 `json:"latest"`
+}
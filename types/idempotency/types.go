@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const MaxKeyLength = 255
+
+// Record is the response stored for an Idempotency-Key of a user.
+// Status is 0 while the first request with the key is still running,
+// LockedUntil is when the reservation of such a request expires and
+// the key can be taken by another one.
+type Record struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Key         string    `gorm:"type:text;primaryKey"`
+	Fingerprint string    `gorm:"type:text;not null"`
+	Status      int       `gorm:"not null"`
+	ContentType string    `gorm:"type:text;not null"`
+	Body        []byte
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	LockedUntil time.Time `gorm:"not null"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
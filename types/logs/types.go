@@ -0,0 +1,5 @@
+package logs
+
+type LevelDto struct {
+	Level string `json:"level" validate:"required,oneof=debug info warn error"`
+}
@@ -1,5 +1,17 @@
 package questions
 
 type QuestionDto struct {
-	Text string `json:"text" validate:"required"`
+	Text string   `json:"text" validate:"required"`
+	Tags []string `json:"tags" validate:"max=5,unique,dive,tag"`
+}
+
+type QuestionUpdateDto struct {
+	Text   string `json:"text" validate:"required"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// RollbackDto is the optional body of a rollback, Reason is saved
+// with the revision of the replaced text.
+type RollbackDto struct {
+	Reason string `json:"reason" validate:"max=500"`
 }
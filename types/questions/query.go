@@ -0,0 +1,22 @@
+package questions
+
+import "github.com/gengeo7/highlitent/types/common"
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// QuestionsQuery selects a page of questions, with Tags set only
+// questions marked by all of the tags are returned.
+type QuestionsQuery struct {
+	Limit  int
+	Cursor *common.Cursor
+	Sort   common.Order
+	Tags   []string
+}
+
+type QuestionsPage struct {
+	Questions  []Question `json:"questions"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
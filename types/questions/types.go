@@ -4,16 +4,44 @@ import (
 	"time"
 
 	"github.com/gengeo7/highlitent/types/answers"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Question struct {
-	ID        uint      `json:"id" gorm:"primarykey"`
-	Text      string    `json:"text" gorm:"type:text;not null"`
-	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+	ID               uint       `json:"id" gorm:"primarykey"`
+	UserID           *uuid.UUID `json:"userID" gorm:"type:uuid"`
+	Text             string     `json:"text" gorm:"type:text;not null"`
+	AcceptedAnswerID *uint      `json:"acceptedAnswerID"`
+	Tags             []string   `json:"tags" gorm:"-"`
+	CreatedAt        time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `json:"updatedAt" gorm:"autoUpdateTime"`
+	// DeletedAt is set while the question is in the trash, such
+	// questions are hidden from every read
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// Revision is a previous text of a question, saved when EditorID
+// replaced it with a new one.
+type Revision struct {
+	ID         uint       `json:"id" gorm:"primarykey"`
+	QuestionID uint       `json:"questionID" gorm:"not null"`
+	Text       string     `json:"text" gorm:"type:text;not null"`
+	EditorID   *uuid.UUID `json:"editorID" gorm:"type:uuid"`
+	Reason     string     `json:"reason" gorm:"type:text;not null"`
+	CreatedAt  time.Time  `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+func (Revision) TableName() string {
+	return "question_revisions"
+}
+
+// QuestionWithAnswers is a question with a page of its answers. The
+// accepted answer is put in front of the first page and is not
+// repeated on the next pages.
 type QuestionWithAnswers struct {
-	Question Question         `json:"question"`
-	Answers  []answers.Answer `json:"answers"`
+	Question          Question         `json:"question"`
+	Answers           []answers.Answer `json:"answers"`
+	AnswersTotal      int64            `json:"answersTotal"`
+	NextAnswersCursor string           `json:"nextAnswersCursor,omitempty"`
 }
@@ -0,0 +1,40 @@
+package search
+
+import "time"
+
+const (
+	DefaultLimit  = 20
+	MaxLimit      = 100
+	MaxTextLength = 200
+)
+
+type Kind string
+
+const (
+	KindQuestion Kind = "question"
+	KindAnswer   Kind = "answer"
+)
+
+// Result is a question or an answer matching the search text. Snippet is a
+// part of the text with the matched words wrapped in <mark></mark>, the
+// rest of the text is HTML-escaped so the snippet can be shown as HTML.
+type Result struct {
+	Type       Kind      `json:"type"`
+	ID         uint      `json:"id"`
+	QuestionID uint      `json:"questionID"`
+	Snippet    string    `json:"snippet"`
+	Rank       float64   `json:"rank"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type SearchQuery struct {
+	Text   string
+	Limit  int
+	Offset int
+}
+
+type SearchPage struct {
+	Results    []Result `json:"results"`
+	Total      int64    `json:"total"`
+	NextOffset *int     `json:"nextOffset,omitempty"`
+}
@@ -0,0 +1,16 @@
+package tags
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+type TagsQuery struct {
+	Limit  int
+	Offset int
+}
+
+type TagsPage struct {
+	Tags       []TagWithCount `json:"tags"`
+	NextOffset *int           `json:"nextOffset,omitempty"`
+}
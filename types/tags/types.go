@@ -0,0 +1,40 @@
+package tags
+
+import (
+	"regexp"
+	"time"
+)
+
+const (
+	MaxPerQuestion = 5
+	MaxNameLength  = 32
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9+#.-]*$`)
+
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Name      string    `json:"name" gorm:"type:text;uniqueIndex;not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+type QuestionTag struct {
+	QuestionID uint `gorm:"primarykey"`
+	TagID      uint `gorm:"primarykey"`
+}
+
+func (QuestionTag) TableName() string {
+	return "question_tags"
+}
+
+// TagWithCount is a tag with the number of questions marked by it.
+type TagWithCount struct {
+	Name      string `json:"name"`
+	Questions int64  `json:"questions"`
+}
+
+// IsValidName reports whether name is a lowercase tag like "go",
+// "postgres" or "c++".
+func IsValidName(name string) bool {
+	return len(name) <= MaxNameLength && nameRe.MatchString(name)
+}
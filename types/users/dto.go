@@ -0,0 +1,23 @@
+package users
+
+import "time"
+
+type UserDto struct {
+	Name     string `json:"name" validate:"required,max=64"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type RoleDto struct {
+	Role Role `json:"role" validate:"required,oneof=user moderator admin"`
+}
+
+type LoginDto struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type TokenDto struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
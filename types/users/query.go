@@ -0,0 +1,16 @@
+package users
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type UsersQuery struct {
+	Limit  int
+	Offset int
+}
+
+type UsersPage struct {
+	Users      []PublicUser `json:"users"`
+	NextOffset *int         `json:"nextOffset,omitempty"`
+}
@@ -0,0 +1,37 @@
+package users
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+type User struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primarykey"`
+	Name         string    `json:"name" gorm:"type:text;not null"`
+	Email        string    `json:"email" gorm:"type:text;uniqueIndex;not null"`
+	Role         Role      `json:"role" gorm:"type:text;not null;default:user"`
+	PasswordHash string    `json:"-" gorm:"type:text;not null"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updatedAt" gorm:"autoUpdateTime"`
+}
+
+// PublicUser is a user as shown to everyone, the email is left out.
+type PublicUser struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (u *User) Public() PublicUser {
+	return PublicUser{ID: u.ID, Name: u.Name, Role: u.Role, CreatedAt: u.CreatedAt}
+}
@@ -45,23 +45,111 @@ type ErrDbCase struct {
 }
 
 func DeadlineDbError(err error) *apierror.ApiError {
-	return apierror.NewApiError(http.StatusRequestTimeout, "достигнут лимит по времени", err)
+	return apierror.NewApiError(http.StatusRequestTimeout, apierror.CodeTimeout, err)
 }
 
 func UnhandledError(err error) *apierror.ApiError {
-	return apierror.NewApiError(http.StatusInternalServerError, "непредвиденная ошибка", err)
+	return apierror.NewApiError(http.StatusInternalServerError, apierror.CodeInternalError, err)
 }
 
 func EmptyDto(err error) *apierror.ApiError {
-	return apierror.NewApiError(http.StatusInternalServerError, "отсутсвует dto", err)
+	return apierror.NewApiError(http.StatusInternalServerError, apierror.CodeEmptyDto, err)
 }
 
 func QuestionNotFound(err error) *apierror.ApiError {
-	return apierror.NewApiError(http.StatusNotFound, "вопрос не найден", err)
+	return apierror.NewApiError(http.StatusNotFound, apierror.CodeQuestionNotFound, err)
 }
 
 func AnswerNotFound(err error) *apierror.ApiError {
-	return apierror.NewApiError(http.StatusNotFound, "ответ не найден", err)
+	return apierror.NewApiError(http.StatusNotFound, apierror.CodeAnswerNotFound, err)
+}
+
+func QuestionNotFoundInTrash(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusNotFound, apierror.CodeQuestionNotFoundInTrash, err)
+}
+
+func AnswerNotFoundInTrash(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusNotFound, apierror.CodeAnswerNotFoundInTrash, err)
+}
+
+func CommentNotFound(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusNotFound, apierror.CodeCommentNotFound, err)
+}
+
+func RevisionNotFound(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusNotFound, apierror.CodeRevisionNotFound, err)
+}
+
+func UserNotFound(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusNotFound, apierror.CodeUserNotFound, err)
+}
+
+func UserAlreadyExists(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusConflict, apierror.CodeUserAlreadyExists, err)
+}
+
+func InvalidCredentials(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusUnauthorized, apierror.CodeInvalidCredentials, err)
+}
+
+func Unauthorized(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusUnauthorized, apierror.CodeUnauthorized, err)
+}
+
+func InvalidCursor(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeInvalidCursor, err)
+}
+
+func Forbidden(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusForbidden, apierror.CodeForbidden, err)
+}
+
+func EmptySearchQuery(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeEmptySearchQuery, err)
+}
+
+func InvalidLogLevel(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeInvalidLogLevel, err)
+}
+
+func InvalidId(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeInvalidId, err)
+}
+
+func InvalidAnswerId(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeInvalidAnswerId, err)
+}
+
+func InvalidRevisionId(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeInvalidRevisionId, err)
+}
+
+func EmptyBody(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeEmptyBody, err)
+}
+
+func InvalidBody(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeInvalidBody, err)
+}
+
+func BodyTooLarge(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusRequestEntityTooLarge, apierror.CodeBodyTooLarge, err)
+}
+
+func InvalidToken(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusUnauthorized, apierror.CodeInvalidToken, err)
+}
+
+func InvalidIdempotencyKey(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusBadRequest, apierror.CodeInvalidIdempotencyKey, err)
+}
+
+func IdempotencyKeyReused(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusConflict, apierror.CodeIdempotencyKeyReused, err)
+}
+
+func IdempotencyKeyInProgress(err error) *apierror.ApiError {
+	return apierror.NewApiError(http.StatusConflict, apierror.CodeIdempotencyKeyInProgress, err)
 }
 
 func TestDbErr(err error, cases ...*ErrDbCase) error {
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gengeo7/highlitent/apierror"
+	"github.com/gengeo7/highlitent/i18n"
+	"github.com/gengeo7/highlitent/types/common"
+)
+
+// QueryParser reads query parameters and collects validation errors
+// in the same format as middleware.ValidateJson.
+type QueryParser struct {
+	query  url.Values
+	lang   i18n.Lang
+	fields map[string]string
+}
+
+func NewQueryParser(r *http.Request) *QueryParser {
+	return &QueryParser{
+		query:  r.URL.Query(),
+		lang:   i18n.FromContext(r.Context()),
+		fields: make(map[string]string),
+	}
+}
+
+func (p *QueryParser) fail(key, msg string, params ...string) {
+	p.fields[key] = i18n.Field(p.lang, msg, append([]string{key}, params...)...)
+}
+
+func (p *QueryParser) Int(key string, def, min, max int) int {
+	val := p.query.Get(key)
+	if val == "" {
+		return def
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		p.fail(key, "number")
+		return def
+	}
+	if i < min {
+		p.fail(key, "min", strconv.Itoa(min))
+		return def
+	}
+	if i > max {
+		p.fail(key, "max", strconv.Itoa(max))
+		return def
+	}
+	return i
+}
+
+func (p *QueryParser) String(key string, required bool, max int) string {
+	val := strings.TrimSpace(p.query.Get(key))
+	if val == "" {
+		if required {
+			p.fail(key, "required")
+		}
+		return ""
+	}
+	if utf8.RuneCountInString(val) > max {
+		p.fail(key, "max-length", strconv.Itoa(max))
+		return ""
+	}
+	return val
+}
+
+// Strings reads a repeated parameter like ?tag=a&tag=b, every value
+// must pass valid and there must be at most max of them.
+func (p *QueryParser) Strings(key string, max int, valid func(string) bool) []string {
+	vals := p.query[key]
+	if len(vals) == 0 {
+		return nil
+	}
+	if len(vals) > max {
+		p.fail(key, "max-items", strconv.Itoa(max))
+		return nil
+	}
+	for _, val := range vals {
+		if !valid(val) {
+			p.fail(key, "format")
+			return nil
+		}
+	}
+	return vals
+}
+
+func (p *QueryParser) Cursor(key string) *common.Cursor {
+	val := p.query.Get(key)
+	if val == "" {
+		return nil
+	}
+	c, err := common.DecodeCursor(val)
+	if err != nil {
+		p.fail(key, "cursor")
+		return nil
+	}
+	return c
+}
+
+func (p *QueryParser) Order(key string, def common.Order, allowed ...common.Order) common.Order {
+	val := p.query.Get(key)
+	if val == "" {
+		return def
+	}
+	for _, o := range allowed {
+		if string(o) == val {
+			return o
+		}
+	}
+	names := make([]string, len(allowed))
+	for i, o := range allowed {
+		names[i] = string(o)
+	}
+	p.fail(key, "oneof", strings.Join(names, " "))
+	return def
+}
+
+func (p *QueryParser) Err() error {
+	if len(p.fields) == 0 {
+		return nil
+	}
+	return apierror.NewValidationError(p.fields)
+}